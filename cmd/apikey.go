@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dodskygge/go_swift/internal/auth"
+	"github.com/dodskygge/go_swift/internal/db"
+	"github.com/dodskygge/go_swift/internal/repository"
+)
+
+const apiKeyUsage = `Usage:
+  go_swift apikey issue -name NAME -scopes codes:read[,codes:write,admin]
+  go_swift apikey revoke -id ID
+  go_swift apikey list
+`
+
+// Runs the "apikey" command used to issue, revoke and list API keys, returning the exit code
+func runAPIKeyCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, apiKeyUsage)
+		return 2
+	}
+
+	database, err := db.ConnectDB()
+	if err != nil {
+		fmt.Fprintln(stderr, "Failed to connect to database:", err)
+		return 1
+	}
+	defer database.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := db.Migrate(ctx, database); err != nil {
+		fmt.Fprintln(stderr, "Failed to migrate database:", err)
+		return 1
+	}
+	repo := &repository.MySQLAPIKeyRepository{DB: database}
+
+	switch args[0] {
+	case "issue":
+		fs := flag.NewFlagSet("issue", flag.ContinueOnError)
+		fs.SetOutput(stderr)
+		name := fs.String("name", "", "name identifying the client")
+		scopes := fs.String("scopes", auth.ScopeRead, "comma-separated scopes: "+strings.Join(auth.KnownScopes, ", "))
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+
+		key, entity, err := auth.IssueAPIKey(ctx, repo, *name, strings.Split(*scopes, ","))
+		if err != nil {
+			fmt.Fprintln(stderr, "Failed to issue API key:", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "Issued API key %d (%s) with scopes %s\n", entity.ID, entity.Name, strings.Join(entity.Scopes, ","))
+		fmt.Fprintf(stdout, "Key: %s\n", key)
+		fmt.Fprintln(stdout, "Store it now, it cannot be shown again.")
+
+	case "revoke":
+		fs := flag.NewFlagSet("revoke", flag.ContinueOnError)
+		fs.SetOutput(stderr)
+		id := fs.Int64("id", 0, "ID of the key to revoke")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+
+		if err := repo.Revoke(ctx, *id); err != nil {
+			fmt.Fprintln(stderr, "Failed to revoke API key:", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "Revoked API key %d\n", *id)
+
+	case "list":
+		keys, err := repo.List(ctx)
+		if err != nil {
+			fmt.Fprintln(stderr, "Failed to list API keys:", err)
+			return 1
+		}
+
+		tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tNAME\tPREFIX\tSCOPES\tCREATED\tREVOKED")
+		for _, key := range keys {
+			revoked := "-"
+			if key.RevokedAt != nil {
+				revoked = key.RevokedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", key.ID, key.Name, key.KeyPrefix,
+				strings.Join(key.Scopes, ","), key.CreatedAt.Format(time.RFC3339), revoked)
+		}
+		tw.Flush()
+
+	default:
+		fmt.Fprint(stderr, apiKeyUsage)
+		return 2
+	}
+
+	return 0
+}
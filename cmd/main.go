@@ -1,19 +1,39 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/dodskygge/go_swift/internal/auth"
+	"github.com/dodskygge/go_swift/internal/config"
 	"github.com/dodskygge/go_swift/internal/db"
+	"github.com/dodskygge/go_swift/internal/grpcapi"
 	"github.com/dodskygge/go_swift/internal/handler"
+	"github.com/dodskygge/go_swift/internal/logging"
+	"github.com/dodskygge/go_swift/internal/metrics"
+	"github.com/dodskygge/go_swift/internal/middleware"
+	"github.com/dodskygge/go_swift/internal/openapi"
 	"github.com/dodskygge/go_swift/internal/repository"
 	"github.com/dodskygge/go_swift/internal/service"
+	"github.com/dodskygge/go_swift/internal/tlsutil"
+	"github.com/dodskygge/go_swift/internal/tracing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
 )
 
 func main() {
-	fmt.Println("SWIFT REST API")
-	fmt.Println("Server is starting...")
+	// Administrative commands, e.g. "go_swift apikey issue -name portal -scopes codes:read"
+	if len(os.Args) > 1 && os.Args[1] == "apikey" {
+		os.Exit(runAPIKeyCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
 
 	// Load environment variables from .env file for local development
 	//if err := godotenv.Load(); err != nil {
@@ -21,37 +41,281 @@ func main() {
 	//	os.Exit(1)
 	//}
 
+	cfg, err := config.Load()
+	if err != nil {
+		slog.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// Setup structured logging, the level can be changed at runtime via /api/v1/admin/log-level
+	level, err := logging.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		slog.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+	handler.LogLevel.Set(level)
+	logger, err := logging.New(os.Stdout, cfg.LogFormat, handler.LogLevel)
+	if err != nil {
+		slog.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(logger)
+
+	slog.Info("SWIFT REST API server is starting")
+
+	// Setup tracing, spans are exported according to OTEL_TRACES_EXPORTER
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Exporter:    cfg.TraceExporter,
+		File:        cfg.TraceFile,
+		ServiceName: cfg.ServiceName,
+	})
+	if err != nil {
+		slog.Error("Failed to setup tracing", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("Failed to flush traces", "error", err)
+		}
+	}()
+
 	// Connect to the database
 	database, err := db.ConnectDB()
 	if err != nil {
-		fmt.Println("Failed to connect to database:", err)
+		slog.Error("Failed to connect to database", "error", err)
 		os.Exit(1)
 	}
 	defer database.Close()
 
+	// Apply pending schema migrations
+	if err := db.Migrate(context.Background(), database); err != nil {
+		slog.Error("Failed to migrate database", "error", err)
+		os.Exit(1)
+	}
+
 	// Initialize repository, service, and set the global service variable
 	repo := &repository.MySQLSwiftRepository{DB: database}
-	swiftService := service.NewSwiftCodeService(repo)
+	swiftService := service.NewSwiftCodeService(repo,
+		service.WithCacheTTL(cfg.CacheTTL),
+		service.WithMaxBatchSize(cfg.BatchMaxCodes),
+	)
 	handler.SwiftService = swiftService
 
-	// Setup HTTP handlers
-	mux := http.NewServeMux()
-	mux.HandleFunc("/api/v1/health", handler.HealthCheckHandler)                          // Health check endpoint
-	mux.HandleFunc("/api/v1/swift-codes/country/", handler.GetSwiftCodesByCountryHandler) // Get SWIFT codes by country
-	mux.HandleFunc("/api/v1/swift-codes", handler.CreateSwiftCodeHandler)                 // Create SWIFT code
-	mux.HandleFunc("/api/v1/swift-codes/", func(w http.ResponseWriter, r *http.Request) { // Handle GET and DELETE for SWIFT codes
-		if r.Method == http.MethodGet {
-			handler.GetSwiftCodeHandler(w, r)
-		} else if r.Method == http.MethodDelete {
-			handler.DeleteSwiftCodeHandler(w, r)
-		} else {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	// Register database metrics exposed on /metrics
+	if err := metrics.RegisterDB(database); err != nil {
+		slog.Error("Failed to register database metrics", "error", err)
+		os.Exit(1)
+	}
+	if err := metrics.RegisterCountryCounts(repo); err != nil {
+		slog.Error("Failed to register database metrics", "error", err)
+		os.Exit(1)
+	}
+
+	// Stored responses of requests sent with an Idempotency-Key header
+	idempotencyKeys := &repository.MySQLIdempotencyRepository{DB: database}
+
+	// Setup HTTP handlers, described by the OpenAPI document served on /api/v1/openapi.json
+	spec, err := openapi.Load()
+	if err != nil {
+		slog.Error("Failed to load OpenAPI document", "error", err)
+		os.Exit(1)
+	}
+	mux, err := newMux(spec, swiftService, idempotencyKeys)
+	if err != nil {
+		slog.Error("Failed to setup routes", "error", err)
+		os.Exit(1)
+	}
+	validateRequests, err := openapi.Validator(spec)
+	if err != nil {
+		slog.Error("Failed to setup request validation", "error", err)
+		os.Exit(1)
+	}
+
+	var authenticators []auth.Authenticator
+	for _, method := range cfg.AuthMethods {
+		switch method {
+		case "apikey":
+			authenticators = append(authenticators, &auth.APIKeyAuthenticator{Store: &repository.MySQLAPIKeyRepository{DB: database}})
+		case "jwt":
+			var keys *auth.KeySet
+			if cfg.JWTJWKSURL != "" {
+				keys, err = auth.NewURLKeySet(context.Background(), cfg.JWTJWKSURL, cfg.JWTJWKSRefresh)
+			} else {
+				keys, err = auth.NewFileKeySet(cfg.JWTJWKSFile)
+			}
+			if err != nil {
+				slog.Error("Failed to load JWKS", "error", err)
+				os.Exit(1)
+			}
+			authenticators = append(authenticators, auth.NewJWTAuthenticator(keys, auth.JWTOptions{
+				Issuer:     cfg.JWTIssuer,
+				Audience:   cfg.JWTAudience,
+				RolesClaim: cfg.JWTRolesClaim,
+				RoleScopes: cfg.JWTRoleScopes,
+				Leeway:     30 * time.Second,
+			}))
+		case "mtls":
+			authenticators = append(authenticators, &auth.ClientCertAuthenticator{SubjectScopes: cfg.TLSClientSubjectScopes})
 		}
-	})
+	}
+
+	// Wrap the mux with tracing, request ID propagation, access logging, metrics, caching headers, CORS,
+	// authentication, rate limiting and request validation
+	route := middleware.MuxRoute(mux)
+	middlewares := []middleware.Middleware{
+		middleware.Tracing(route),
+		middleware.RequestID,
+		middleware.AccessLog(logger, route),
+		middleware.Metrics(route),
+		middleware.CacheControl(route, cfg.CacheControl),
+	}
+	if len(cfg.CORSAllowedOrigins) > 0 {
+		middlewares = append(middlewares, middleware.CORS(middleware.CORSOptions{
+			AllowedOrigins:   cfg.CORSAllowedOrigins,
+			AllowedMethods:   cfg.CORSAllowedMethods,
+			AllowedHeaders:   cfg.CORSAllowedHeaders,
+			ExposedHeaders:   cfg.CORSExposedHeaders,
+			MaxAge:           cfg.CORSMaxAge,
+			AllowCredentials: cfg.CORSAllowCredentials,
+		}))
+	}
+
+	// Rate limit per API key/principal, or per client IP for anonymous callers and failed authentication
+	// attempts, which are counted before authentication so that credentials cannot be guessed without limit
+	rateLimits := middleware.RateLimitOptions{
+		Default:           middleware.Limit(cfg.RateLimit),
+		Routes:            make(map[string]middleware.Limit),
+		TrustForwardedFor: cfg.RateLimitTrustProxy,
+	}
+	for pattern, limit := range cfg.RateLimitRoutes {
+		rateLimits.Routes[pattern] = middleware.Limit(limit)
+	}
+	rateLimiter := middleware.NewRateLimiter(rateLimits)
+	if len(authenticators) > 0 {
+		middlewares = append(middlewares, rateLimiter.AuthFailures, auth.Middleware(routePolicy(), route, authenticators...))
+	} else {
+		slog.Warn("Authentication is disabled by AUTH_METHODS=none, every route is open to anyone")
+	}
+	middlewares = append(middlewares, rateLimiter.Middleware(route))
+
+	// Bound batch bodies before they are buffered, then reject requests that do not match the OpenAPI document
+	// before they reach the handlers
+	middlewares = append(middlewares, middleware.MaxBodySize(route, handler.MaxBodySizes(swiftService.MaxBatchSize())))
+	middlewares = append(middlewares, validateRequests)
+
+	server := &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: middleware.Chain(mux, middlewares...),
+	}
 
-	fmt.Println("Started successfully. Listening on port 8080...")
-	if err := http.ListenAndServe(":8080", mux); err != nil {
-		fmt.Println("Error starting server:", err)
+	// Serve HTTPS when a certificate is configured, renewed certificates are picked up without a restart
+	if cfg.TLSCertFile != "" {
+		server.TLSConfig, err = tlsutil.ServerConfig(tlsutil.ServerOptions{
+			CertFile:     cfg.TLSCertFile,
+			KeyFile:      cfg.TLSKeyFile,
+			ClientCAFile: cfg.TLSClientCAFile,
+			ClientAuth:   cfg.TLSClientAuth,
+		})
+		if err != nil {
+			slog.Error("Failed to setup TLS", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Serve the gRPC API with the same service, credentials, rate limits and TLS settings as the REST API
+	var grpcServer *grpc.Server
+	var grpcHealth *health.Server
+	if cfg.GRPCPort != "off" {
+		var opts []grpc.ServerOption
+		if server.TLSConfig != nil {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(server.TLSConfig.Clone())))
+		}
+		grpcServer, grpcHealth = grpcapi.NewServer(swiftService, grpcapi.Options{
+			Authenticators: authenticators,
+			RateLimiter:    rateLimiter,
+			Logger:         logger,
+		}, opts...)
+
+		listener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			slog.Error("Failed to listen for gRPC", "error", err)
+			os.Exit(1)
+		}
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				slog.Error("Error serving gRPC", "error", err)
+				os.Exit(1)
+			}
+		}()
+		slog.Info("gRPC server started", "port", cfg.GRPCPort)
+	}
+
+	// Shut down gracefully on SIGINT/SIGTERM so in-flight requests and spans are not lost
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Expire stored idempotent responses
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			deleted, err := idempotencyKeys.DeleteExpired(ctx, time.Now().UTC().Add(-cfg.IdempotencyKeyTTL))
+			if err != nil {
+				slog.Error("Failed to delete expired idempotency keys", "error", err)
+			} else if deleted > 0 {
+				slog.Info("Expired idempotency keys deleted", "count", deleted)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	// Closed once in-flight requests are done, so that main returns, flushing spans and closing the database,
+	// only afterwards
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		grpcStopped := make(chan struct{})
+		if grpcServer != nil {
+			// Report NOT_SERVING so load balancers stop sending calls, then let in-flight calls finish
+			grpcHealth.Shutdown()
+			go func() {
+				grpcServer.GracefulStop()
+				close(grpcStopped)
+			}()
+		}
+		server.Shutdown(shutdownCtx)
+
+		// Cancel the gRPC calls still running when the deadline expires
+		if grpcServer != nil {
+			select {
+			case <-grpcStopped:
+			case <-shutdownCtx.Done():
+				grpcServer.Stop()
+				<-grpcStopped
+			}
+		}
+	}()
+
+	slog.Info("Started successfully", "port", cfg.Port, "tls", server.TLSConfig != nil, "client_auth", cfg.TLSClientAuth)
+	if server.TLSConfig != nil {
+		// The key pair is served by TLSConfig.GetCertificate
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("Error starting server", "error", err)
 		os.Exit(1)
 	}
+	<-shutdownDone
+	slog.Info("Server stopped")
 }
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/dodskygge/go_swift/internal/auth"
+	"github.com/dodskygge/go_swift/internal/graphqlapi"
+	"github.com/dodskygge/go_swift/internal/handler"
+	"github.com/dodskygge/go_swift/internal/metrics"
+	"github.com/dodskygge/go_swift/internal/middleware"
+	"github.com/dodskygge/go_swift/internal/openapi"
+	"github.com/dodskygge/go_swift/internal/service"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Registers the API routes; every route must be described in the OpenAPI document and listed in routePolicy
+func newMux(spec *openapi3.T, svc *service.SwiftCodeService, idempotencyKeys middleware.IdempotencyStore) (*http.ServeMux, error) {
+	specHandler, err := openapi.Handler(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	// Creating SWIFT codes, one by one or in bulk, is safe to retry with an Idempotency-Key header
+	createHandler := middleware.Idempotency(idempotencyKeys)(http.HandlerFunc(handler.CreateSwiftCodeHandler))
+	bulkHandler := middleware.Idempotency(idempotencyKeys)(http.HandlerFunc(handler.BulkWriteHandler))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/health", handler.HealthCheckHandler)                          // Health check endpoint
+	mux.Handle("/metrics", metrics.Handler())                                             // Prometheus metrics
+	mux.Handle("/api/v1/openapi.json", specHandler)                                       // OpenAPI document
+	mux.Handle("/api/v1/docs/", openapi.DocsHandler())                                    // Swagger UI
+	mux.HandleFunc("/api/v1/admin/log-level", handler.LogLevelHandler)                    // Get or change the log level
+	mux.HandleFunc("/api/v1/admin/audit-log", handler.AuditLogHandler)                    // Query the audit log of all changes
+	mux.HandleFunc("/api/v1/swift-codes/country/", handler.GetSwiftCodesByCountryHandler) // Get SWIFT codes by country
+	mux.HandleFunc("/api/v1/countries", handler.ListCountriesHandler)                     // List countries with code counts
+	mux.HandleFunc("/api/v1/institutions/", handler.GetInstitutionHandler)                // Get SWIFT codes by institution code
+	mux.HandleFunc("/api/v1/stats", handler.StatsHandler)                                 // Statistics of all SWIFT codes
+	mux.HandleFunc("/api/v1/stats/countries/", handler.CountryStatsHandler)               // Statistics of the SWIFT codes of a country
+	mux.Handle("/api/v1/swift-codes", createHandler)                                      // Create SWIFT code
+	mux.HandleFunc("/api/v1/swift-codes/batch-lookup", handler.BatchLookupHandler)        // Look up many SWIFT codes at once
+	mux.Handle("/api/v1/swift-codes/bulk", bulkHandler)                                   // Create or update many SWIFT codes in one transaction
+	mux.HandleFunc("/api/v1/swift-codes/export", handler.ExportSwiftCodesHandler)         // Export SWIFT codes as CSV, JSON or NDJSON
+	mux.HandleFunc("/api/v1/swift-codes/search", handler.SearchSwiftCodesHandler)         // Search SWIFT codes by code prefix or bank name
+	mux.Handle("/api/v1/graphql", graphqlapi.NewHandler(svc))                             // GraphQL queries over countries, banks and branches
+	mux.HandleFunc("/api/v1/swift-codes/", func(w http.ResponseWriter, r *http.Request) { // Handle GET, PUT and DELETE for SWIFT codes
+		if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/history") {
+			handler.SwiftCodeHistoryHandler(w, r)
+		} else if r.Method == http.MethodGet {
+			handler.GetSwiftCodeHandler(w, r)
+		} else if r.Method == http.MethodPut {
+			handler.UpdateSwiftCodeHandler(w, r)
+		} else if r.Method == http.MethodDelete {
+			handler.DeleteSwiftCodeHandler(w, r)
+		} else {
+			handler.WriteError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+	return mux, nil
+}
+
+// Scopes required per route when authentication is enabled
+func routePolicy() auth.Policy {
+	return auth.Policy{
+		{Method: "*", Route: "/api/v1/health", Scope: auth.Public},
+		{Method: "*", Route: "/metrics", Scope: auth.ScopeAdmin},
+		{Method: "*", Route: "/api/v1/openapi.json", Scope: auth.Public},
+		{Method: "*", Route: "/api/v1/docs/", Scope: auth.Public},
+		{Method: "*", Route: "/api/v1/admin/log-level", Scope: auth.ScopeAdmin},
+		{Method: http.MethodGet, Route: "/api/v1/admin/audit-log", Scope: auth.ScopeAdmin},
+		{Method: http.MethodGet, Route: "/api/v1/swift-codes/country/", Scope: auth.ScopeRead},
+		{Method: http.MethodGet, Route: "/api/v1/countries", Scope: auth.ScopeRead},
+		{Method: http.MethodGet, Route: "/api/v1/institutions/", Scope: auth.ScopeRead},
+		{Method: http.MethodGet, Route: "/api/v1/stats", Scope: auth.ScopeRead},
+		{Method: http.MethodGet, Route: "/api/v1/stats/countries/", Scope: auth.ScopeRead},
+		{Method: http.MethodPost, Route: "/api/v1/swift-codes", Scope: auth.ScopeWrite},
+		{Method: http.MethodPost, Route: "/api/v1/swift-codes/batch-lookup", Scope: auth.ScopeRead},
+		{Method: http.MethodPost, Route: "/api/v1/swift-codes/bulk", Scope: auth.ScopeWrite},
+		{Method: http.MethodGet, Route: "/api/v1/swift-codes/export", Scope: auth.ScopeRead},
+		{Method: http.MethodGet, Route: "/api/v1/swift-codes/search", Scope: auth.ScopeRead},
+		{Method: http.MethodPost, Route: "/api/v1/graphql", Scope: auth.ScopeRead},
+		// Also covers /api/v1/swift-codes/{swift-code}/history
+		{Method: http.MethodGet, Route: "/api/v1/swift-codes/", Scope: auth.ScopeRead},
+		{Method: http.MethodPut, Route: "/api/v1/swift-codes/", Scope: auth.ScopeWrite},
+		{Method: http.MethodDelete, Route: "/api/v1/swift-codes/", Scope: auth.ScopeWrite},
+	}
+}
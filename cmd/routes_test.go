@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/dodskygge/go_swift/internal/auth"
+	"github.com/dodskygge/go_swift/internal/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// Path parameters of the OpenAPI document
+var pathParam = regexp.MustCompile(`\{[^}]+\}`)
+
+// Checks that every operation of the OpenAPI document is routed and has a policy rule, and that every rule
+// belongs to a documented operation, so the document cannot drift from the handlers
+func TestRoutesMatchSpec(t *testing.T) {
+	spec, err := openapi.Load()
+	assert.NoError(t, err)
+	mux, err := newMux(spec, nil, nil)
+	assert.NoError(t, err)
+	policy := routePolicy()
+
+	documented := map[auth.Rule]bool{}
+	for path, item := range spec.Paths.Map() {
+		target := pathParam.ReplaceAllString(path, "TESTUS33XXX")
+		for method := range item.Operations() {
+			_, pattern := mux.Handler(httptest.NewRequest(method, target, nil))
+			if !assert.NotEmpty(t, pattern, "%s %s is documented but not routed", method, path) {
+				continue
+			}
+
+			rule, ok := findRule(policy, method, pattern)
+			if assert.True(t, ok, "%s %s has no policy rule", method, path) {
+				documented[rule] = true
+			}
+		}
+	}
+
+	for _, rule := range policy {
+		assert.True(t, documented[rule], "%s %s is not documented", rule.Method, rule.Route)
+	}
+}
+
+// Returns the policy rule applied to a method and route pattern
+func findRule(policy auth.Policy, method, pattern string) (auth.Rule, bool) {
+	for _, rule := range policy {
+		if rule.Route == pattern && (rule.Method == "*" || rule.Method == method) {
+			return rule, true
+		}
+	}
+	return auth.Rule{}, false
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/user"
+
+	"github.com/dodskygge/go_swift/internal/auth"
+	"github.com/dodskygge/go_swift/internal/db"
+	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/dodskygge/go_swift/internal/repository"
+	"github.com/dodskygge/go_swift/internal/service"
+	"github.com/dodskygge/go_swift/pkg/client"
+)
+
+// Operations of the commands, served by a running API server or directly by the database
+type backend interface {
+	Get(ctx context.Context, swiftCode string) (*model.SwiftCodeResponse, error)
+	Country(ctx context.Context, countryISO2 string) (*model.SwiftCodesByCountryResponse, error)
+	Search(ctx context.Context, search model.SwiftCodeSearch) ([]model.SwiftCodeResponse, string, error)
+	Create(ctx context.Context, req model.CreateSwiftCodeRequest) error
+	Delete(ctx context.Context, swiftCode string, version int64) error
+	Import(ctx context.Context, records []model.CreateSwiftCodeRequest, mode string, upsert bool) (*model.BulkWriteResponse, error)
+	Export(ctx context.Context, filter model.SwiftCodeFilter, fn func(model.CreateSwiftCodeRequest) error) error
+}
+
+// Backend calling the REST API of a running server
+type serverBackend struct {
+	client *client.Client
+}
+
+func (b *serverBackend) Get(ctx context.Context, swiftCode string) (*model.SwiftCodeResponse, error) {
+	return b.client.GetSwiftCode(ctx, swiftCode)
+}
+
+func (b *serverBackend) Country(ctx context.Context, countryISO2 string) (*model.SwiftCodesByCountryResponse, error) {
+	return b.client.GetByCountry(ctx, countryISO2)
+}
+
+func (b *serverBackend) Search(ctx context.Context, search model.SwiftCodeSearch) ([]model.SwiftCodeResponse, string, error) {
+	result, err := b.client.Search(ctx, search)
+	if err != nil {
+		return nil, "", err
+	}
+	return result.SwiftCodes, result.Next, nil
+}
+
+func (b *serverBackend) Create(ctx context.Context, req model.CreateSwiftCodeRequest) error {
+	return b.client.Create(ctx, req)
+}
+
+func (b *serverBackend) Delete(ctx context.Context, swiftCode string, version int64) error {
+	if version == 0 {
+		return b.client.Delete(ctx, swiftCode, "")
+	}
+
+	// The API expects back the ETag of the code, the server checking the version again on delete
+	code, err := b.client.GetSwiftCode(ctx, swiftCode)
+	if err != nil {
+		return err
+	}
+	if code.Version != version {
+		return fmt.Errorf("SWIFT code is at version %d: %w", code.Version, client.ErrVersionMismatch)
+	}
+	return b.client.Delete(ctx, swiftCode, code.ETag)
+}
+
+func (b *serverBackend) Import(ctx context.Context, records []model.CreateSwiftCodeRequest, mode string, upsert bool) (*model.BulkWriteResponse, error) {
+	return b.client.BulkWrite(ctx, records, mode, upsert)
+}
+
+func (b *serverBackend) Export(ctx context.Context, filter model.SwiftCodeFilter, fn func(model.CreateSwiftCodeRequest) error) error {
+	return b.client.Export(ctx, filter, fn)
+}
+
+// Backend using the service on the database configured by the DB_* environment variables, without a server
+type directBackend struct {
+	service *service.SwiftCodeService
+	// principal the changes are recorded under in the audit log, named after the operating system user
+	principal *auth.Principal
+}
+
+// Connects to the database, returning the backend and a function closing the connection
+func newDirectBackend() (*directBackend, func(), error) {
+	database, err := db.ConnectDB()
+	if err != nil {
+		return nil, nil, err
+	}
+	repo := &repository.MySQLSwiftRepository{DB: database}
+	return &directBackend{service: service.NewSwiftCodeService(repo), principal: cliPrincipal()}, func() { database.Close() }, nil
+}
+
+// Returns the principal of changes made with --direct, "cli:<user>" for the operating system user
+func cliPrincipal() *auth.Principal {
+	name := "unknown"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	return &auth.Principal{ID: "cli:" + name, Name: name, Scopes: []string{auth.ScopeAdmin}}
+}
+
+func (b *directBackend) Get(ctx context.Context, swiftCode string) (*model.SwiftCodeResponse, error) {
+	result, err := b.service.GetSwiftCodeDetails(ctx, swiftCode)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, model.ErrNotFound
+	}
+	return result, nil
+}
+
+func (b *directBackend) Country(ctx context.Context, countryISO2 string) (*model.SwiftCodesByCountryResponse, error) {
+	return b.service.GetSwiftCodesByCountry(ctx, countryISO2)
+}
+
+func (b *directBackend) Search(ctx context.Context, search model.SwiftCodeSearch) ([]model.SwiftCodeResponse, string, error) {
+	return b.service.SearchSwiftCodes(ctx, search)
+}
+
+func (b *directBackend) Create(ctx context.Context, req model.CreateSwiftCodeRequest) error {
+	return b.service.CreateSwiftCode(auth.WithPrincipal(ctx, b.principal), req)
+}
+
+func (b *directBackend) Delete(ctx context.Context, swiftCode string, version int64) error {
+	return b.service.DeleteSwiftCode(auth.WithPrincipal(ctx, b.principal), swiftCode, version)
+}
+
+func (b *directBackend) Import(ctx context.Context, records []model.CreateSwiftCodeRequest, mode string, upsert bool) (*model.BulkWriteResponse, error) {
+	return b.service.BulkWrite(auth.WithPrincipal(ctx, b.principal), records, mode, upsert)
+}
+
+func (b *directBackend) Export(ctx context.Context, filter model.SwiftCodeFilter, fn func(model.CreateSwiftCodeRequest) error) error {
+	return b.service.ExportSwiftCodes(ctx, filter, fn)
+}
@@ -0,0 +1,387 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/dodskygge/go_swift/internal/service"
+	"github.com/spf13/cobra"
+)
+
+// Completions of boolean filter flags
+var boolValues = []string{"true", "false"}
+
+func (c *cli) getCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get SWIFT_CODE",
+		Short: "Show a SWIFT code with its branches",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, done, err := c.backend()
+			if err != nil {
+				return err
+			}
+			defer done()
+
+			code, err := b.Get(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return printResult(cmd.OutOrStdout(), c.output, code, codeTable(code))
+		},
+	}
+}
+
+func (c *cli) countryCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "country COUNTRY_ISO2",
+		Short: "List the SWIFT codes of a country",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, done, err := c.backend()
+			if err != nil {
+				return err
+			}
+			defer done()
+
+			country, err := b.Country(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			if country == nil || country.CountryISO2 == "" {
+				country = &model.SwiftCodesByCountryResponse{CountryISO2: strings.ToUpper(args[0])}
+			}
+			if country.SwiftCodes == nil {
+				country.SwiftCodes = []model.SwiftCodeMinimalResponse{}
+			}
+			return printResult(cmd.OutOrStdout(), c.output, country, countryTable(country))
+		},
+	}
+}
+
+func (c *cli) searchCommand() *cobra.Command {
+	var search model.SwiftCodeSearch
+	var hq string
+	cmd := &cobra.Command{
+		Use:   "search [QUERY]",
+		Short: "Search SWIFT codes by code prefix or bank name",
+		Long: `Search SWIFT codes whose code starts with QUERY or whose bank name contains it, ordered by code.
+When more results are available, the value to pass to --after for the next page is printed to stderr.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				search.Query = args[0]
+			}
+			var err error
+			if search.Filter.IsHeadquarter, err = parseOptionalBool("hq", hq); err != nil {
+				return err
+			}
+
+			b, done, err := c.backend()
+			if err != nil {
+				return err
+			}
+			defer done()
+
+			results, next, err := b.Search(cmd.Context(), search)
+			if err != nil {
+				return err
+			}
+			if err := printResult(cmd.OutOrStdout(), c.output, results, searchTable(results)); err != nil {
+				return err
+			}
+			if next != "" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "More results: --after %s\n", next)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&search.Filter.CountryISO2, "country", "", "only codes of this ISO2 country code")
+	cmd.Flags().StringVar(&hq, "hq", "", "true for headquarters only, false for branches only")
+	cmd.Flags().IntVar(&search.Limit, "limit", service.DefaultSearchLimit, fmt.Sprintf("results per page, at most %d", service.MaxSearchLimit))
+	cmd.Flags().StringVar(&search.After, "after", "", "continue after this SWIFT code")
+	cmd.RegisterFlagCompletionFunc("hq", cobra.FixedCompletions(boolValues, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+func (c *cli) createCommand() *cobra.Command {
+	var req model.CreateSwiftCodeRequest
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a SWIFT code",
+		Long: `Create a SWIFT code. Codes ending in XXX are headquarters unless --hq says otherwise, and the API
+rejects a --hq value that does not match the code.`,
+		Example: `  swiftctl create --swift-code ALBPPLPWXXX --bank-name "ALIOR BANK" --address "LOPUSZANSKA 38 D" \
+    --country-iso2 PL`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("hq") {
+				req.IsHeadquarter = strings.HasSuffix(req.SwiftCode, "XXX")
+			}
+
+			b, done, err := c.backend()
+			if err != nil {
+				return err
+			}
+			defer done()
+
+			if err := b.Create(cmd.Context(), req); err != nil {
+				return err
+			}
+			message := model.MessageResponse{Message: "SWIFT code created successfully"}
+			return printResult(cmd.OutOrStdout(), c.output, message, messageTable(message))
+		},
+	}
+
+	cmd.Flags().StringVar(&req.SwiftCode, "swift-code", "", "SWIFT code")
+	cmd.Flags().StringVar(&req.BankName, "bank-name", "", "name of the bank")
+	cmd.Flags().StringVar(&req.Address, "address", "", "address of the bank")
+	cmd.Flags().StringVar(&req.CountryISO2, "country-iso2", "", "ISO2 country code")
+	cmd.Flags().StringVar(&req.CountryName, "country-name", "", "country name, derived from --country-iso2 when omitted")
+	cmd.Flags().BoolVar(&req.IsHeadquarter, "hq", false, "whether the code is a headquarters (default: code ends in XXX)")
+	for _, name := range []string{"swift-code", "bank-name", "address", "country-iso2"} {
+		cmd.MarkFlagRequired(name)
+	}
+	return cmd
+}
+
+func (c *cli) deleteCommand() *cobra.Command {
+	var version int64
+	cmd := &cobra.Command{
+		Use:   "delete SWIFT_CODE",
+		Short: "Delete a SWIFT code",
+		Long: `Delete a SWIFT code. With --version, the code is only deleted if it is still at that version, as shown
+in the ETag returned by the API.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if version < 0 {
+				return errors.New("--version must not be negative")
+			}
+
+			b, done, err := c.backend()
+			if err != nil {
+				return err
+			}
+			defer done()
+
+			if err := b.Delete(cmd.Context(), args[0], version); err != nil {
+				return err
+			}
+			message := model.MessageResponse{Message: "SWIFT code deleted successfully"}
+			return printResult(cmd.OutOrStdout(), c.output, message, messageTable(message))
+		},
+	}
+
+	cmd.Flags().Int64Var(&version, "version", 0, "expected version of the code, 0 deletes any version")
+	return cmd
+}
+
+func (c *cli) importCommand() *cobra.Command {
+	var format, mode string
+	var upsert bool
+	var batchSize int
+	cmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Create or update SWIFT codes from a CSV, JSON or NDJSON file",
+		Long: `Create SWIFT codes from a file in the export format, "-" reading standard input. Records are written
+in transactions of --batch-size records; in all-or-nothing mode the import stops at the first batch that is
+not committed, leaving earlier batches written. The outcome of each record is printed and the command fails
+when any record failed.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if batchSize < 1 {
+				return errors.New("--batch-size must be positive")
+			}
+			records, err := readRecordFile(args[0], format, cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+
+			b, done, err := c.backend()
+			if err != nil {
+				return err
+			}
+			defer done()
+
+			result := &model.BulkWriteResponse{Mode: mode, Committed: true, Items: []model.BulkItemResult{}}
+			for start := 0; start < len(records); start += batchSize {
+				end := min(start+batchSize, len(records))
+				batch, err := b.Import(cmd.Context(), records[start:end], mode, upsert)
+				if err != nil {
+					return err
+				}
+
+				result.Created += batch.Created
+				result.Updated += batch.Updated
+				result.Failed += batch.Failed
+				result.Committed = batch.Committed
+				for _, item := range batch.Items {
+					item.Index += start
+					result.Items = append(result.Items, item)
+				}
+				if !batch.Committed {
+					break
+				}
+			}
+
+			if err := printResult(cmd.OutOrStdout(), c.output, result, itemsTable(result.Items)); err != nil {
+				return err
+			}
+			if result.Failed > 0 || !result.Committed {
+				return fmt.Errorf("%d of %d records failed", result.Failed, len(records))
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Created %d and updated %d SWIFT codes\n", result.Created, result.Updated)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "file format: csv, json or ndjson (default: from the file extension)")
+	cmd.Flags().StringVar(&mode, "mode", model.BulkAllOrNothing, "all-or-nothing or best-effort")
+	cmd.Flags().BoolVar(&upsert, "upsert", false, "update codes that already exist")
+	cmd.Flags().IntVar(&batchSize, "batch-size", service.DefaultMaxBatchSize, "records written per transaction")
+	cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(recordFormats, cobra.ShellCompDirectiveNoFileComp))
+	cmd.RegisterFlagCompletionFunc("mode", cobra.FixedCompletions([]string{model.BulkAllOrNothing, model.BulkBestEffort}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+func (c *cli) exportCommand() *cobra.Command {
+	var format, file, hq string
+	var filter model.SwiftCodeFilter
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write SWIFT codes to a CSV, JSON or NDJSON file",
+		Long:  `Write the SWIFT codes matching the filters, ordered by code, in the format read by import.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if format, err = recordFormat(format, file); err != nil {
+				return err
+			}
+			if filter.IsHeadquarter, err = parseOptionalBool("hq", hq); err != nil {
+				return err
+			}
+
+			b, done, err := c.backend()
+			if err != nil {
+				return err
+			}
+			defer done()
+
+			out := cmd.OutOrStdout()
+			if file != "" && file != "-" {
+				f, err := os.Create(file)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				out = f
+			}
+
+			w := newRecordWriter(out, format)
+			if err := b.Export(cmd.Context(), filter, w.write); err != nil {
+				return err
+			}
+			if err := w.close(); err != nil {
+				return err
+			}
+			if file != "" && file != "-" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d SWIFT codes to %s\n", w.count, file)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "file format: csv, json or ndjson (default: from the file extension, or json)")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "write to this file instead of standard output")
+	cmd.Flags().StringVar(&filter.CountryISO2, "country", "", "only codes of this ISO2 country code")
+	cmd.Flags().StringVar(&hq, "hq", "", "true for headquarters only, false for branches only")
+	cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(recordFormats, cobra.ShellCompDirectiveNoFileComp))
+	cmd.RegisterFlagCompletionFunc("hq", cobra.FixedCompletions(boolValues, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+func (c *cli) validateCommand() *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:   "validate FILE",
+		Short: "Check a CSV, JSON or NDJSON file of SWIFT codes without importing it",
+		Long: `Check every record of a file with the rules applied on import, and report duplicate codes. Runs
+offline, without a server or database. The invalid records are printed and the command fails when there are any.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			records, err := readRecordFile(args[0], format, cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+
+			invalid := []model.BulkItemResult{}
+			seen := make(map[string]int, len(records))
+			for i, record := range records {
+				err := service.ValidateSwiftCode(record)
+				if first, ok := seen[record.SwiftCode]; ok && err == nil {
+					err = fmt.Errorf("duplicate of record %d", first)
+				} else if !ok {
+					seen[record.SwiftCode] = i
+				}
+				if err != nil {
+					invalid = append(invalid, model.BulkItemResult{Index: i, SwiftCode: record.SwiftCode, Status: model.BulkStatusFailed, Error: err.Error()})
+				}
+			}
+
+			if len(invalid) > 0 {
+				if err := printResult(cmd.OutOrStdout(), c.output, invalid, itemsTable(invalid)); err != nil {
+					return err
+				}
+				return fmt.Errorf("%d of %d records are invalid", len(invalid), len(records))
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "All %d records are valid\n", len(records))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "file format: csv, json or ndjson (default: from the file extension)")
+	cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(recordFormats, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+// Reads the records of a file, "-" reading stdin
+func readRecordFile(path, format string, stdin io.Reader) ([]model.CreateSwiftCodeRequest, error) {
+	format, err := recordFormat(format, path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	records, err := readRecords(r, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return records, nil
+}
+
+// Parses a true/false flag that may be left empty
+func parseOptionalBool(name, value string) (*bool, error) {
+	if value == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s %q, expected true or false", name, value)
+	}
+	return &b, nil
+}
@@ -0,0 +1,100 @@
+// Command swiftctl looks up and manages SWIFT codes, either through a running API server or directly in the
+// database configured by the DB_* environment variables.
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/dodskygge/go_swift/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := newRootCommand(&cli{}).ExecuteContext(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+// Settings shared by the commands
+type cli struct {
+	server  string
+	apiKey  string
+	token   string
+	direct  bool
+	timeout time.Duration
+	output  string
+
+	// connect returns the backend of the commands and a function releasing it, set in tests
+	connect func(c *cli) (backend, func(), error)
+}
+
+// Returns the backend selected by the flags
+func (c *cli) backend() (backend, func(), error) {
+	if c.connect != nil {
+		return c.connect(c)
+	}
+	if c.direct {
+		return newDirectBackend()
+	}
+
+	opts := []client.Option{client.WithTimeout(c.timeout), client.WithUserAgent("swiftctl")}
+	if c.apiKey != "" {
+		opts = append(opts, client.WithAPIKey(c.apiKey))
+	}
+	if c.token != "" {
+		opts = append(opts, client.WithBearerToken(c.token))
+	}
+	apiClient, err := client.New(c.server, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &serverBackend{client: apiClient}, func() {}, nil
+}
+
+func newRootCommand(c *cli) *cobra.Command {
+	root := &cobra.Command{
+		Use:   "swiftctl",
+		Short: "Look up and manage SWIFT codes",
+		Long: `swiftctl looks up and manages SWIFT codes through the REST API of a running server (--server) or,
+with --direct, in the MySQL database configured by the DB_* environment variables.
+
+Shell completion scripts are printed by "swiftctl completion bash|zsh|fish|powershell".`,
+		SilenceUsage: true,
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&c.server, "server", envOr("SWIFTCTL_SERVER", "http://localhost:8080"), "URL of the API server ($SWIFTCTL_SERVER)")
+	flags.StringVar(&c.apiKey, "api-key", os.Getenv("SWIFTCTL_API_KEY"), "API key sent to the server ($SWIFTCTL_API_KEY)")
+	flags.StringVar(&c.token, "token", os.Getenv("SWIFTCTL_TOKEN"), "bearer token sent to the server ($SWIFTCTL_TOKEN)")
+	flags.BoolVar(&c.direct, "direct", false, "use the database configured by the DB_* environment variables instead of a server")
+	flags.DurationVar(&c.timeout, "timeout", 30*time.Second, "timeout of each request to the server, including whole exports; 0 disables it")
+	flags.StringVarP(&c.output, "output", "o", "table", "output format: table, json or csv")
+	root.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(outputFormats, cobra.ShellCompDirectiveNoFileComp))
+
+	root.AddCommand(
+		c.getCommand(),
+		c.countryCommand(),
+		c.searchCommand(),
+		c.createCommand(),
+		c.deleteCommand(),
+		c.importCommand(),
+		c.exportCommand(),
+		c.validateCommand(),
+	)
+	return root
+}
+
+// Returns the value of an environment variable, or def when it is unset
+func envOr(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
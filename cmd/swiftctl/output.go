@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dodskygge/go_swift/internal/model"
+)
+
+// Output formats of the lookup commands
+var outputFormats = []string{"table", "json", "csv"}
+
+// Rows of a result printed as a table or CSV
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// Columns of SWIFT codes in tables
+var codeHeader = []string{"SWIFT CODE", "BANK NAME", "ADDRESS", "COUNTRY", "HQ"}
+
+func (t *table) addCode(swiftCode, bankName, address, countryISO2 string, isHeadquarter bool) {
+	t.rows = append(t.rows, []string{swiftCode, bankName, address, countryISO2, strconv.FormatBool(isHeadquarter)})
+}
+
+// Prints a result in the output format: value as JSON, or the rows of t as a table or CSV
+func printResult(w io.Writer, format string, value any, t *table) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case "csv":
+		out := csv.NewWriter(w)
+		out.Write(t.header)
+		out.WriteAll(t.rows)
+		return out.Error()
+	case "table":
+		out := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(out, strings.Join(t.header, "\t"))
+		for _, row := range t.rows {
+			fmt.Fprintln(out, strings.Join(row, "\t"))
+		}
+		return out.Flush()
+	}
+	return fmt.Errorf("invalid output format %q, expected table, json or csv", format)
+}
+
+// Rows of a SWIFT code followed by its branches
+func codeTable(code *model.SwiftCodeResponse) *table {
+	t := &table{header: codeHeader}
+	t.addCode(code.SwiftCode, code.BankName, code.Address, code.CountryISO2, code.IsHeadquarter)
+	for _, branch := range code.Branches {
+		t.addCode(branch.SwiftCode, branch.BankName, branch.Address, branch.CountryISO2, branch.IsHeadquarter)
+	}
+	return t
+}
+
+// Rows of the SWIFT codes of a country
+func countryTable(country *model.SwiftCodesByCountryResponse) *table {
+	t := &table{header: codeHeader}
+	for _, code := range country.SwiftCodes {
+		t.addCode(code.SwiftCode, code.BankName, code.Address, code.CountryISO2, code.IsHeadquarter)
+	}
+	return t
+}
+
+// Rows of search results
+func searchTable(codes []model.SwiftCodeResponse) *table {
+	t := &table{header: codeHeader}
+	for _, code := range codes {
+		t.addCode(code.SwiftCode, code.BankName, code.Address, code.CountryISO2, code.IsHeadquarter)
+	}
+	return t
+}
+
+// Row of a confirmation message
+func messageTable(message model.MessageResponse) *table {
+	return &table{header: []string{"MESSAGE"}, rows: [][]string{{message.Message}}}
+}
+
+// Rows of the per-record outcome of an import or validation
+func itemsTable(items []model.BulkItemResult) *table {
+	t := &table{header: []string{"INDEX", "SWIFT CODE", "STATUS", "ERROR"}}
+	for _, item := range items {
+		t.rows = append(t.rows, []string{strconv.Itoa(item.Index), item.SwiftCode, item.Status, item.Error})
+	}
+	return t
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/dodskygge/go_swift/internal/model"
+)
+
+// Formats of record files, the same as the export endpoint
+var recordFormats = []string{"csv", "json", "ndjson"}
+
+// Column layout of CSV record files, matching the export endpoint
+var recordCSVHeader = []string{"swiftCode", "bankName", "address", "countryISO2", "countryName", "isHeadquarter"}
+
+// Returns the record format given explicitly or by the file extension, json when neither tells
+func recordFormat(format, path string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			format = "csv"
+		case ".ndjson", ".jsonl":
+			format = "ndjson"
+		default:
+			format = "json"
+		}
+	}
+	for _, f := range recordFormats {
+		if f == format {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("invalid format %q, expected csv, json or ndjson", format)
+}
+
+// Reads the records of a file in the given format
+func readRecords(r io.Reader, format string) ([]model.CreateSwiftCodeRequest, error) {
+	switch format {
+	case "csv":
+		return readCSVRecords(r)
+	case "ndjson":
+		var records []model.CreateSwiftCodeRequest
+		decoder := json.NewDecoder(r)
+		for line := 1; ; line++ {
+			var record model.CreateSwiftCodeRequest
+			err := decoder.Decode(&record)
+			if err == io.EOF {
+				return records, nil
+			}
+			if err != nil {
+				return nil, fmt.Errorf("record %d: %w", line, err)
+			}
+			records = append(records, record)
+		}
+	default:
+		var records []model.CreateSwiftCodeRequest
+		if err := json.NewDecoder(r).Decode(&records); err != nil {
+			return nil, err
+		}
+		return records, nil
+	}
+}
+
+// Reads CSV records whose header names the columns, in any order
+func readCSVRecords(r io.Reader) ([]model.CreateSwiftCodeRequest, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range recordCSVHeader {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	var records []model.CreateSwiftCodeRequest
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		isHeadquarter, err := strconv.ParseBool(row[columns["isHeadquarter"]])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid isHeadquarter %q", line, row[columns["isHeadquarter"]])
+		}
+		records = append(records, model.CreateSwiftCodeRequest{
+			SwiftCode:     row[columns["swiftCode"]],
+			BankName:      row[columns["bankName"]],
+			Address:       row[columns["address"]],
+			CountryISO2:   row[columns["countryISO2"]],
+			CountryName:   row[columns["countryName"]],
+			IsHeadquarter: isHeadquarter,
+		})
+	}
+}
+
+// Writes records one at a time in a record file format
+type recordWriter struct {
+	format string
+	buf    *bufio.Writer
+	csv    *csv.Writer
+	count  int
+}
+
+func newRecordWriter(w io.Writer, format string) *recordWriter {
+	buf := bufio.NewWriter(w)
+	return &recordWriter{format: format, buf: buf, csv: csv.NewWriter(buf)}
+}
+
+func (w *recordWriter) write(record model.CreateSwiftCodeRequest) error {
+	defer func() { w.count++ }()
+
+	switch w.format {
+	case "csv":
+		if w.count == 0 {
+			if err := w.csv.Write(recordCSVHeader); err != nil {
+				return err
+			}
+		}
+		return w.csv.Write([]string{record.SwiftCode, record.BankName, record.Address, record.CountryISO2, record.CountryName, strconv.FormatBool(record.IsHeadquarter)})
+	case "ndjson":
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		w.buf.Write(data)
+		return w.buf.WriteByte('\n')
+	default:
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		if w.count == 0 {
+			w.buf.WriteByte('[')
+		} else {
+			w.buf.WriteByte(',')
+		}
+		_, err = w.buf.Write(data)
+		return err
+	}
+}
+
+// Completes the file, writing the CSV header or JSON brackets of an empty export
+func (w *recordWriter) close() error {
+	switch w.format {
+	case "csv":
+		if w.count == 0 {
+			w.csv.Write(recordCSVHeader)
+		}
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+	case "json":
+		if w.count == 0 {
+			w.buf.WriteByte('[')
+		}
+		w.buf.WriteString("]\n")
+	}
+	return w.buf.Flush()
+}
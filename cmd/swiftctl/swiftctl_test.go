@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/dodskygge/go_swift/pkg/client"
+	"github.com/stretchr/testify/assert"
+)
+
+// Backend keeping SWIFT codes in memory
+type fakeBackend struct {
+	codes   map[string]model.CreateSwiftCodeRequest
+	imports [][]model.CreateSwiftCodeRequest
+}
+
+func newFakeBackend(records ...model.CreateSwiftCodeRequest) *fakeBackend {
+	b := &fakeBackend{codes: map[string]model.CreateSwiftCodeRequest{}}
+	for _, record := range records {
+		b.codes[record.SwiftCode] = record
+	}
+	return b
+}
+
+func (b *fakeBackend) Get(_ context.Context, swiftCode string) (*model.SwiftCodeResponse, error) {
+	record, ok := b.codes[swiftCode]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return &model.SwiftCodeResponse{SwiftCode: record.SwiftCode, BankName: record.BankName, Address: record.Address,
+		CountryISO2: record.CountryISO2, CountryName: record.CountryName, IsHeadquarter: record.IsHeadquarter}, nil
+}
+
+func (b *fakeBackend) Country(_ context.Context, countryISO2 string) (*model.SwiftCodesByCountryResponse, error) {
+	return nil, nil
+}
+
+func (b *fakeBackend) Search(_ context.Context, search model.SwiftCodeSearch) ([]model.SwiftCodeResponse, string, error) {
+	var results []model.SwiftCodeResponse
+	for _, record := range b.codes {
+		if strings.HasPrefix(record.SwiftCode, search.Query) {
+			results = append(results, model.SwiftCodeResponse{SwiftCode: record.SwiftCode, BankName: record.BankName})
+		}
+	}
+	return results, "NEXTUS33XXX", nil
+}
+
+func (b *fakeBackend) Create(_ context.Context, req model.CreateSwiftCodeRequest) error {
+	b.codes[req.SwiftCode] = req
+	return nil
+}
+
+func (b *fakeBackend) Delete(_ context.Context, swiftCode string, _ int64) error {
+	delete(b.codes, swiftCode)
+	return nil
+}
+
+func (b *fakeBackend) Import(_ context.Context, records []model.CreateSwiftCodeRequest, mode string, _ bool) (*model.BulkWriteResponse, error) {
+	b.imports = append(b.imports, records)
+	result := &model.BulkWriteResponse{Mode: mode, Committed: true}
+	for i, record := range records {
+		if record.BankName == "" {
+			result.Committed = false
+			result.Failed++
+			result.Items = append(result.Items, model.BulkItemResult{Index: i, SwiftCode: record.SwiftCode, Status: model.BulkStatusFailed, Error: "bankName cannot be empty"})
+			continue
+		}
+		result.Created++
+		result.Items = append(result.Items, model.BulkItemResult{Index: i, SwiftCode: record.SwiftCode, Status: model.BulkStatusCreated})
+	}
+	return result, nil
+}
+
+func (b *fakeBackend) Export(_ context.Context, filter model.SwiftCodeFilter, fn func(model.CreateSwiftCodeRequest) error) error {
+	for _, code := range []string{"TESTPLPWXXX", "TESTUS33XXX"} {
+		if record, ok := b.codes[code]; ok && (filter.CountryISO2 == "" || filter.CountryISO2 == record.CountryISO2) {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Runs swiftctl with args against b, returning stdout, stderr and the error of the command
+func run(b backend, stdin string, args ...string) (string, string, error) {
+	c := &cli{connect: func(*cli) (backend, func(), error) { return b, func() {}, nil }}
+	cmd := newRootCommand(c)
+	var stdout, stderr bytes.Buffer
+	cmd.SetArgs(args)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	err := cmd.Execute()
+	return stdout.String(), stderr.String(), err
+}
+
+var testRecords = []model.CreateSwiftCodeRequest{
+	{SwiftCode: "TESTUS33XXX", BankName: "Test Bank", Address: "123 Main St", CountryISO2: "US", CountryName: "UNITED STATES", IsHeadquarter: true},
+	{SwiftCode: "TESTPLPWXXX", BankName: "Test Bank, PL", Address: "1 Prosta", CountryISO2: "PL", CountryName: "POLAND", IsHeadquarter: true},
+}
+
+// Unit test for the lookup commands and output formats
+func TestGetOutputFormats(t *testing.T) {
+	backend := newFakeBackend(testRecords...)
+
+	stdout, _, err := run(backend, "", "get", "TESTUS33XXX")
+	assert.NoError(t, err)
+	assert.Equal(t, "SWIFT CODE   BANK NAME  ADDRESS      COUNTRY  HQ\nTESTUS33XXX  Test Bank  123 Main St  US       true\n", stdout)
+
+	stdout, _, err = run(backend, "", "get", "TESTPLPWXXX", "-o", "csv")
+	assert.NoError(t, err)
+	assert.Equal(t, "SWIFT CODE,BANK NAME,ADDRESS,COUNTRY,HQ\nTESTPLPWXXX,\"Test Bank, PL\",1 Prosta,PL,true\n", stdout)
+
+	stdout, _, err = run(backend, "", "get", "TESTUS33XXX", "-o", "json")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, `"bankName": "Test Bank"`)
+
+	_, _, err = run(backend, "", "get", "MISSUS33XXX")
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	_, _, err = run(backend, "", "get", "TESTUS33XXX", "-o", "yaml")
+	assert.ErrorContains(t, err, "invalid output format")
+
+	// Countries without codes are listed empty
+	stdout, _, err = run(backend, "", "country", "de", "-o", "json")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, `"countryISO2": "DE"`)
+	assert.Contains(t, stdout, `"swiftCodes": []`)
+
+	_, stderr, err := run(backend, "", "search", "TESTUS", "--hq", "true")
+	assert.NoError(t, err)
+	assert.Equal(t, "More results: --after NEXTUS33XXX\n", stderr)
+
+	_, _, err = run(backend, "", "search", "--hq", "maybe")
+	assert.ErrorContains(t, err, "invalid --hq")
+}
+
+// Unit test for create and delete
+func TestCreateAndDelete(t *testing.T) {
+	backend := newFakeBackend()
+
+	_, _, err := run(backend, "", "create", "--swift-code", "TESTUS33XXX", "--bank-name", "Test Bank")
+	assert.ErrorContains(t, err, "required flag(s)")
+
+	_, _, err = run(backend, "", "create", "--swift-code", "TESTUS33XXX", "--bank-name", "Test Bank", "--address", "123 Main St",
+		"--country-iso2", "US", "--country-name", "United States")
+	assert.NoError(t, err)
+	assert.True(t, backend.codes["TESTUS33XXX"].IsHeadquarter)
+
+	stdout, _, err := run(backend, "", "delete", "TESTUS33XXX", "--version", "2")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "SWIFT code deleted successfully")
+	assert.Empty(t, backend.codes)
+}
+
+// Unit test for import in batches
+func TestImport(t *testing.T) {
+	backend := newFakeBackend()
+	ndjson := `{"swiftCode":"TESTUS33XXX","bankName":"Test Bank","address":"1","countryISO2":"US","countryName":"USA","isHeadquarter":true}
+{"swiftCode":"TESTUS33ABC","bankName":"Test Bank","address":"2","countryISO2":"US","countryName":"USA","isHeadquarter":false}
+{"swiftCode":"TESTUS33DEF","bankName":"","address":"3","countryISO2":"US","countryName":"USA","isHeadquarter":false}
+`
+	stdout, _, err := run(backend, ndjson, "import", "-", "--format", "ndjson", "--batch-size", "2")
+	assert.ErrorContains(t, err, "1 of 3 records failed")
+	assert.Len(t, backend.imports, 2)
+	// Indexes refer to the whole file
+	assert.Contains(t, stdout, "2      TESTUS33DEF  failed   bankName cannot be empty")
+
+	backend = newFakeBackend()
+	_, stderr, err := run(backend, ndjson[:strings.LastIndex(ndjson[:len(ndjson)-1], "\n")+1], "import", "-", "--format", "ndjson")
+	assert.NoError(t, err)
+	assert.Equal(t, "Created 2 and updated 0 SWIFT codes\n", stderr)
+	assert.Len(t, backend.imports, 1)
+}
+
+// Unit test for export and reading the exported file back
+func TestExportAndValidate(t *testing.T) {
+	backend := newFakeBackend(testRecords...)
+	dir := t.TempDir()
+
+	for _, format := range recordFormats {
+		file := filepath.Join(dir, "codes."+format)
+		_, _, err := run(backend, "", "export", "--file", file)
+		assert.NoError(t, err)
+
+		f, err := os.Open(file)
+		assert.NoError(t, err)
+		records, err := readRecords(f, format)
+		f.Close()
+		assert.NoError(t, err)
+		assert.Equal(t, []model.CreateSwiftCodeRequest{testRecords[1], testRecords[0]}, records, format)
+
+		_, stderr, err := run(backend, "", "validate", file)
+		assert.NoError(t, err)
+		assert.Equal(t, "All 2 records are valid\n", stderr)
+	}
+
+	stdout, _, err := run(backend, "", "export", "--format", "csv", "--country", "PL")
+	assert.NoError(t, err)
+	assert.Equal(t, "swiftCode,bankName,address,countryISO2,countryName,isHeadquarter\nTESTPLPWXXX,\"Test Bank, PL\",1 Prosta,PL,POLAND,true\n", stdout)
+
+	stdout, _, err = run(backend, "", "export", "--country", "DE")
+	assert.NoError(t, err)
+	assert.Equal(t, "[]\n", stdout)
+
+	// Invalid and duplicate records are reported
+	csv := "swiftCode,bankName,address,countryISO2,countryName,isHeadquarter\n" +
+		"TESTUS33XXX,Test Bank,1,US,USA,true\n" +
+		"TESTUS33ABC,Test Bank,2,US,USA,true\n" +
+		"TESTUS33XXX,Test Bank,1,US,USA,true\n"
+	stdout, _, err = run(backend, csv, "validate", "-", "--format", "csv", "-o", "csv")
+	assert.ErrorContains(t, err, "2 of 3 records are invalid")
+	assert.Equal(t, "INDEX,SWIFT CODE,STATUS,ERROR\n"+
+		"1,TESTUS33ABC,failed,SWIFT code does not match the provided isHeadquarter value\n"+
+		"2,TESTUS33XXX,failed,duplicate of record 0\n", stdout)
+
+	_, _, err = run(backend, "swiftCode,bankName\n", "validate", "-", "--format", "csv")
+	assert.ErrorContains(t, err, `missing column "address"`)
+}
+
+// Unit test for the server backend against the REST API
+func TestServerBackend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("X-API-Key"))
+		switch r.URL.Path {
+		case "/api/v1/swift-codes/TESTUS33XXX":
+			if r.Method == http.MethodDelete {
+				assert.Equal(t, `"2-0123456789abcdef"`, r.Header.Get("If-Match"))
+				w.Write([]byte(`{"message":"SWIFT code deleted successfully"}`))
+				return
+			}
+			w.Header().Set("ETag", `"2-0123456789abcdef"`)
+			w.Write([]byte(`{"address":"123 Main St","bankName":"Test Bank","countryISO2":"US","countryName":"UNITED STATES","isHeadquarter":true,"swiftCode":"TESTUS33XXX","branches":[]}`))
+		case "/api/v1/swift-codes/search":
+			assert.Equal(t, "hq=true&limit=1&q=TEST", r.URL.RawQuery)
+			w.Write([]byte(`{"swiftCodes":[{"address":"123 Main St","bankName":"Test Bank","countryISO2":"US","countryName":"UNITED STATES","isHeadquarter":true,"swiftCode":"TESTUS33XXX"}],"next":"TESTUS33XXX"}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	cmd := newRootCommand(&cli{})
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{"get", "TESTUS33XXX", "--server", server.URL, "--api-key", "secret", "-o", "csv"})
+	assert.NoError(t, cmd.Execute())
+	assert.Equal(t, "SWIFT CODE,BANK NAME,ADDRESS,COUNTRY,HQ\nTESTUS33XXX,Test Bank,123 Main St,US,true\n", stdout.String())
+
+	cmd = newRootCommand(&cli{})
+	stdout.Reset()
+	var stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{"search", "TEST", "--hq", "true", "--limit", "1", "--server", server.URL, "--api-key", "secret", "-o", "csv"})
+	assert.NoError(t, cmd.Execute())
+	assert.Equal(t, "SWIFT CODE,BANK NAME,ADDRESS,COUNTRY,HQ\nTESTUS33XXX,Test Bank,123 Main St,US,true\n", stdout.String())
+	assert.Equal(t, "More results: --after TESTUS33XXX\n", stderr.String())
+
+	// Deletes send back the ETag of the code at the expected version
+	cmd = newRootCommand(&cli{})
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{"delete", "TESTUS33XXX", "--version", "2", "--server", server.URL, "--api-key", "secret"})
+	assert.NoError(t, cmd.Execute())
+
+	cmd = newRootCommand(&cli{})
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{"delete", "TESTUS33XXX", "--version", "1", "--server", server.URL, "--api-key", "secret"})
+	assert.ErrorIs(t, cmd.Execute(), client.ErrVersionMismatch)
+}
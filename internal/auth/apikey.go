@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dodskygge/go_swift/internal/model"
+)
+
+// APIKeyHeader carries an API key; "Authorization: ApiKey <key>" is accepted as well.
+const APIKeyHeader = "X-API-Key"
+
+const (
+	apiKeyPrefix       = "gsk_"
+	apiKeyPrefixLength = 12
+)
+
+// APIKeyStore persists API keys.
+type APIKeyStore interface {
+	Create(ctx context.Context, key *model.APIKeyEntity) (int64, error)
+	GetByHash(ctx context.Context, hash string) (*model.APIKeyEntity, error)
+}
+
+// HashAPIKey returns the hex-encoded SHA-256 hash under which a key is stored.
+// Keys are random 256-bit values, so a fast hash is sufficient.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IssueAPIKey generates a new key with the given scopes and stores its hash.
+// The returned plain key is shown once and cannot be recovered later.
+func IssueAPIKey(ctx context.Context, store APIKeyStore, name string, scopes []string) (string, *model.APIKeyEntity, error) {
+	if name == "" {
+		return "", nil, fmt.Errorf("name cannot be empty")
+	}
+	if err := ValidateScopes(scopes); err != nil {
+		return "", nil, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	entity := &model.APIKeyEntity{
+		Name:      name,
+		KeyPrefix: key[:apiKeyPrefixLength],
+		KeyHash:   HashAPIKey(key),
+		Scopes:    scopes,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	id, err := store.Create(ctx, entity)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to store API key: %w", err)
+	}
+	entity.ID = id
+
+	return key, entity, nil
+}
+
+// APIKeyAuthenticator authenticates requests carrying an API key.
+type APIKeyAuthenticator struct {
+	Store APIKeyStore
+}
+
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		scheme, value, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !ok || !strings.EqualFold(scheme, "ApiKey") {
+			return nil, nil
+		}
+		key = strings.TrimSpace(value)
+	}
+
+	entity, err := a.Store.GetByHash(r.Context(), HashAPIKey(key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up API key: %w", err)
+	}
+	if entity == nil || entity.RevokedAt != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return &Principal{
+		ID:     fmt.Sprintf("apikey:%d", entity.ID),
+		Name:   entity.Name,
+		Scopes: entity.Scopes,
+	}, nil
+}
+
+func (a *APIKeyAuthenticator) Challenge() string {
+	return `ApiKey realm="go_swift"`
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// In-memory API key store for testing
+type memoryKeyStore struct {
+	keys []*model.APIKeyEntity
+}
+
+func (s *memoryKeyStore) Create(ctx context.Context, key *model.APIKeyEntity) (int64, error) {
+	key.ID = int64(len(s.keys) + 1)
+	s.keys = append(s.keys, key)
+	return key.ID, nil
+}
+
+func (s *memoryKeyStore) GetByHash(ctx context.Context, hash string) (*model.APIKeyEntity, error) {
+	for _, key := range s.keys {
+		if key.KeyHash == hash {
+			return key, nil
+		}
+	}
+	return nil, nil
+}
+
+// Unit test for IssueAPIKey
+func TestIssueAPIKey(t *testing.T) {
+	store := &memoryKeyStore{}
+
+	key, entity, err := IssueAPIKey(context.Background(), store, "reconciliation", []string{ScopeRead})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key, "gsk_"))
+	assert.Equal(t, key[:12], entity.KeyPrefix)
+	assert.Equal(t, HashAPIKey(key), entity.KeyHash)
+	assert.NotContains(t, entity.KeyHash, key)
+
+	_, _, err = IssueAPIKey(context.Background(), store, "bad", []string{"codes:delete"})
+	assert.Error(t, err)
+}
+
+// Unit test for APIKeyAuthenticator
+func TestAPIKeyAuthenticator(t *testing.T) {
+	store := &memoryKeyStore{}
+	key, _, err := IssueAPIKey(context.Background(), store, "portal", []string{ScopeRead})
+	assert.NoError(t, err)
+	a := &APIKeyAuthenticator{Store: store}
+
+	// No credentials
+	principal, err := a.Authenticate(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.NoError(t, err)
+	assert.Nil(t, principal)
+
+	// Valid key in X-API-Key
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(APIKeyHeader, key)
+	principal, err = a.Authenticate(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "apikey:1", principal.ID)
+	assert.Equal(t, []string{ScopeRead}, principal.Scopes)
+
+	// Valid key in the Authorization header
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "ApiKey "+key)
+	principal, err = a.Authenticate(req)
+	assert.NoError(t, err)
+	assert.NotNil(t, principal)
+
+	// Unknown key
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(APIKeyHeader, "gsk_unknown")
+	_, err = a.Authenticate(req)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	// Revoked key
+	revokedAt := time.Now()
+	store.keys[0].RevokedAt = &revokedAt
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(APIKeyHeader, key)
+	_, err = a.Authenticate(req)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+// Scopes granted to clients.
+const (
+	ScopeRead  = "codes:read"
+	ScopeWrite = "codes:write"
+	ScopeAdmin = "admin"
+)
+
+// KnownScopes lists every scope that can be granted.
+var KnownScopes = []string{ScopeRead, ScopeWrite, ScopeAdmin}
+
+// ErrInvalidCredentials is returned when credentials are present but cannot be verified.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	// ID identifies the caller, e.g. "apikey:12".
+	ID     string
+	Name   string
+	Scopes []string
+}
+
+// HasScope reports whether the principal was granted the scope; admin implies every scope.
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope) || slices.Contains(p.Scopes, ScopeAdmin)
+}
+
+// Authenticator verifies one kind of credentials carried by a request.
+type Authenticator interface {
+	// Authenticate returns (nil, nil) when the request carries no credentials of its kind.
+	Authenticate(r *http.Request) (*Principal, error)
+	// Challenge returns the WWW-Authenticate challenge sent with 401 responses.
+	Challenge() string
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated principal.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the authenticated principal stored in ctx, or nil.
+func PrincipalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// ValidateScopes checks that every scope is known.
+func ValidateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return fmt.Errorf("at least one scope is required")
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(KnownScopes, scope) {
+			return fmt.Errorf("unknown scope: %s", scope)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Unit test for Principal.HasScope
+func TestPrincipalHasScope(t *testing.T) {
+	reader := &Principal{ID: "apikey:1", Scopes: []string{ScopeRead}}
+	assert.True(t, reader.HasScope(ScopeRead))
+	assert.False(t, reader.HasScope(ScopeWrite))
+
+	admin := &Principal{ID: "apikey:2", Scopes: []string{ScopeAdmin}}
+	assert.True(t, admin.HasScope(ScopeWrite))
+}
+
+// Unit test for the principal context helpers
+func TestPrincipalContext(t *testing.T) {
+	assert.Nil(t, PrincipalFromContext(context.Background()))
+
+	p := &Principal{ID: "apikey:1"}
+	assert.Equal(t, p, PrincipalFromContext(WithPrincipal(context.Background(), p)))
+}
+
+// Unit test for ValidateScopes
+func TestValidateScopes(t *testing.T) {
+	assert.NoError(t, ValidateScopes([]string{ScopeRead, ScopeWrite}))
+	assert.Error(t, ValidateScopes(nil))
+	assert.Error(t, ValidateScopes([]string{"codes:delete"}))
+}
@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ClientCertAuthenticator authenticates requests by the TLS client certificate verified during the handshake.
+type ClientCertAuthenticator struct {
+	// SubjectScopes maps certificate subjects, either the common name or the full
+	// distinguished name such as "CN=portal,O=Example Bank", to the scopes they grant.
+	SubjectScopes map[string][]string
+}
+
+func (a *ClientCertAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, nil
+	}
+	cert := r.TLS.VerifiedChains[0][0]
+
+	// Certificates signed by the client CA are only authorized when their subject is listed
+	scopes, ok := a.SubjectScopes[cert.Subject.CommonName]
+	if !ok {
+		scopes, ok = a.SubjectScopes[cert.Subject.String()]
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: client certificate subject %q is not authorized", ErrInvalidCredentials, cert.Subject.String())
+	}
+
+	return &Principal{
+		ID:     "cert:" + cert.Subject.CommonName,
+		Name:   cert.Subject.CommonName,
+		Scopes: scopes,
+	}, nil
+}
+
+// Challenge is empty since client certificates are requested during the TLS handshake.
+func (a *ClientCertAuthenticator) Challenge() string {
+	return ""
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Builds a request carrying a verified client certificate with the given subject
+func requestWithClientCert(subject pkix.Name) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/TESTUS33XXX", nil)
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: subject}}}}
+	return req
+}
+
+// Unit test for ClientCertAuthenticator
+func TestClientCertAuthenticator(t *testing.T) {
+	a := &ClientCertAuthenticator{SubjectScopes: map[string][]string{
+		"reconciliation":                {ScopeRead},
+		"CN=portal,O=Example Bank,C=PL": {ScopeRead, ScopeWrite},
+	}}
+
+	// Plain HTTP or no client certificate
+	principal, err := a.Authenticate(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.NoError(t, err)
+	assert.Nil(t, principal)
+
+	// Matched by common name
+	principal, err = a.Authenticate(requestWithClientCert(pkix.Name{CommonName: "reconciliation", Organization: []string{"Example Bank"}}))
+	assert.NoError(t, err)
+	assert.Equal(t, "cert:reconciliation", principal.ID)
+	assert.Equal(t, []string{ScopeRead}, principal.Scopes)
+
+	// Matched by distinguished name
+	principal, err = a.Authenticate(requestWithClientCert(pkix.Name{CommonName: "portal", Organization: []string{"Example Bank"}, Country: []string{"PL"}}))
+	assert.NoError(t, err)
+	assert.True(t, principal.HasScope(ScopeWrite))
+
+	// Signed by the client CA but not authorized
+	_, err = a.Authenticate(requestWithClientCert(pkix.Name{CommonName: "intruder"}))
+	assert.True(t, errors.Is(err, ErrInvalidCredentials))
+	assert.Empty(t, a.Challenge())
+}
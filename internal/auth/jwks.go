@@ -0,0 +1,244 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// KeySet holds the public keys used to verify bearer tokens, loaded from a JWKS file or URL.
+// Keys fetched from a URL are refreshed periodically and when a token names an unknown key ID.
+type KeySet struct {
+	source     string
+	isURL      bool
+	client     *http.Client
+	refresh    time.Duration
+	minRefresh time.Duration
+
+	// refreshes coalesces concurrent refreshes into one fetch
+	refreshes singleflight.Group
+
+	mu        sync.RWMutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+	// attemptedAt is the time of the last fetch, successful or not, limiting refreshes to one per minRefresh
+	attemptedAt time.Time
+}
+
+// NewFileKeySet loads a JWKS document from a local file.
+func NewFileKeySet(path string) (*KeySet, error) {
+	ks := &KeySet{source: path}
+	if err := ks.load(context.Background()); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// NewURLKeySet fetches a JWKS document from url and refreshes it every refresh interval.
+func NewURLKeySet(ctx context.Context, url string, refresh time.Duration) (*KeySet, error) {
+	ks := &KeySet{
+		source:     url,
+		isURL:      true,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		refresh:    refresh,
+		minRefresh: 30 * time.Second,
+	}
+	if err := ks.load(ctx); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// Key returns the public key with the given key ID.
+func (ks *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	ks.mu.RLock()
+	key, ok := ks.keys[kid]
+	stale := ks.isURL && time.Since(ks.fetchedAt) > ks.refresh
+	canRefresh := ks.isURL && time.Since(ks.attemptedAt) > ks.minRefresh
+	ks.mu.RUnlock()
+
+	// Refresh on schedule, or early when an unknown key ID may indicate key rotation, backing off after
+	// any fetch so that failures or tokens with made-up key IDs do not hammer the JWKS endpoint. Concurrent
+	// callers share one fetch, which is not canceled with the context of the caller that started it.
+	if (stale || !ok) && canRefresh {
+		_, err, _ := ks.refreshes.Do(ks.source, func() (any, error) {
+			return nil, ks.load(context.WithoutCancel(ctx))
+		})
+		if err != nil {
+			slog.WarnContext(ctx, "failed to refresh JWKS", "source", ks.source, "error", err)
+		} else {
+			ks.mu.RLock()
+			key, ok = ks.keys[kid]
+			ks.mu.RUnlock()
+		}
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("unknown key id: %q", kid)
+	}
+	return key, nil
+}
+
+// Reads and parses the JWKS document from the source
+func (ks *KeySet) load(ctx context.Context) error {
+	var data []byte
+	var err error
+	if ks.isURL {
+		data, err = ks.fetch(ctx)
+		ks.mu.Lock()
+		ks.attemptedAt = time.Now()
+		ks.mu.Unlock()
+	} else {
+		data, err = os.ReadFile(ks.source)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS: %w", err)
+	}
+
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.fetchedAt = time.Now()
+	ks.mu.Unlock()
+	return nil
+}
+
+// Downloads the JWKS document
+func (ks *KeySet) fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ks.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ks.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseJWKS parses the signing keys of a JWKS document, indexed by key ID.
+// RSA, EC (P-256, P-384, P-521) and Ed25519 keys are supported; other keys are skipped.
+func ParseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JWKS document: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", k.Kid, err)
+		}
+		if key != nil {
+			keys[k.Kid] = key
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS document contains no signing keys")
+	}
+	return keys, nil
+}
+
+// Converts a JWK into a public key, returning nil for unsupported key types
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on curve %s", k.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, nil
+}
+
+// Decodes a base64url-encoded big-endian integer
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("invalid base64url integer")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Builds a JWKS document containing the RSA public key under kid
+func rsaJWKS(t *testing.T, kid string, key *rsa.PublicKey) []byte {
+	doc := map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"alg": "RS256",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	data, err := json.Marshal(doc)
+	assert.NoError(t, err)
+	return data
+}
+
+// Unit test for ParseJWKS with RSA and EC keys
+func TestParseJWKS(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	data := []byte(`{"keys":[
+		{"kty":"EC","kid":"ec-1","crv":"P-256","x":"` + base64.RawURLEncoding.EncodeToString(ecKey.X.FillBytes(make([]byte, 32))) +
+		`","y":"` + base64.RawURLEncoding.EncodeToString(ecKey.Y.FillBytes(make([]byte, 32))) + `"},
+		{"kty":"RSA","kid":"enc-1","use":"enc","n":"AQAB","e":"AQAB"},
+		{"kty":"oct","kid":"hmac-1","k":"c2VjcmV0"}
+	]}`)
+
+	keys, err := ParseJWKS(data)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assert.True(t, ecKey.PublicKey.Equal(keys["ec-1"]))
+
+	_, err = ParseJWKS([]byte(`{"keys":[]}`))
+	assert.Error(t, err)
+}
+
+// Unit test for a key set loaded from a file
+func TestFileKeySet(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	assert.NoError(t, os.WriteFile(path, rsaJWKS(t, "key-1", &rsaKey.PublicKey), 0o600))
+
+	ks, err := NewFileKeySet(path)
+	assert.NoError(t, err)
+
+	key, err := ks.Key(context.Background(), "key-1")
+	assert.NoError(t, err)
+	assert.True(t, rsaKey.PublicKey.Equal(key))
+
+	_, err = ks.Key(context.Background(), "key-2")
+	assert.Error(t, err)
+}
+
+// Unit test for a key set fetched from a URL picking up rotated keys
+func TestURLKeySetRotation(t *testing.T) {
+	first, _ := rsa.GenerateKey(rand.Reader, 2048)
+	second, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	current := rsaJWKS(t, "key-1", &first.PublicKey)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(current)
+	}))
+	defer server.Close()
+
+	ks, err := NewURLKeySet(context.Background(), server.URL, time.Hour)
+	assert.NoError(t, err)
+	ks.minRefresh = 0
+
+	_, err = ks.Key(context.Background(), "key-1")
+	assert.NoError(t, err)
+
+	// An unknown key ID triggers a refresh
+	current = rsaJWKS(t, "key-2", &second.PublicKey)
+	key, err := ks.Key(context.Background(), "key-2")
+	assert.NoError(t, err)
+	assert.True(t, second.PublicKey.Equal(key))
+}
+
+// Unit test for a key set backing off after failed refreshes and sharing concurrent ones
+func TestURLKeySetRefreshLimits(t *testing.T) {
+	first, _ := rsa.GenerateKey(rand.Reader, 2048)
+	second, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	var fetches atomic.Int32
+	status := http.StatusOK
+	current := rsaJWKS(t, "key-1", &first.PublicKey)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fetches.Add(1) > 1 && status == http.StatusOK {
+			<-release
+		}
+		w.WriteHeader(status)
+		w.Write(current)
+	}))
+	defer server.Close()
+
+	ks, err := NewURLKeySet(context.Background(), server.URL, time.Hour)
+	assert.NoError(t, err)
+	ks.minRefresh = time.Hour
+
+	// A failed refresh is not retried before minRefresh
+	status = http.StatusServiceUnavailable
+	ks.attemptedAt = time.Time{}
+	for range 3 {
+		_, err = ks.Key(context.Background(), "key-2")
+		assert.Error(t, err)
+	}
+	assert.Equal(t, int32(2), fetches.Load())
+
+	// Callers waiting for a refresh share one fetch
+	status = http.StatusOK
+	current = rsaJWKS(t, "key-2", &second.PublicKey)
+	ks.attemptedAt = time.Time{}
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key, err := ks.Key(context.Background(), "key-2")
+			assert.NoError(t, err)
+			assert.True(t, second.PublicKey.Equal(key))
+		}()
+	}
+	for fetches.Load() < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(3), fetches.Load())
+}
@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTOptions configures how bearer tokens are validated and mapped to scopes.
+type JWTOptions struct {
+	Issuer   string
+	Audience string
+	// RolesClaim is the claim holding the caller's roles, a dotted path such as "realm_access.roles" is allowed.
+	RolesClaim string
+	// RoleScopes maps roles to the scopes they grant; when empty, roles are used as scopes directly.
+	RoleScopes map[string][]string
+	Leeway     time.Duration
+}
+
+// JWTAuthenticator authenticates requests carrying a bearer token signed by a key in the key set.
+type JWTAuthenticator struct {
+	keys   *KeySet
+	opts   JWTOptions
+	parser *jwt.Parser
+}
+
+// NewJWTAuthenticator creates an authenticator validating tokens against keys.
+func NewJWTAuthenticator(keys *KeySet, opts JWTOptions) *JWTAuthenticator {
+	if opts.RolesClaim == "" {
+		opts.RolesClaim = "roles"
+	}
+
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(opts.Leeway),
+	}
+	if opts.Issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(opts.Issuer))
+	}
+	if opts.Audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(opts.Audience))
+	}
+
+	return &JWTAuthenticator{keys: keys, opts: opts, parser: jwt.NewParser(parserOpts...)}
+}
+
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, nil
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := a.parser.ParseWithClaims(strings.TrimSpace(token), claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return a.keys.Key(r.Context(), kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return nil, fmt.Errorf("%w: missing sub claim", ErrInvalidCredentials)
+	}
+	name, _ := claims["preferred_username"].(string)
+	if name == "" {
+		name = subject
+	}
+
+	return &Principal{
+		ID:     "jwt:" + subject,
+		Name:   name,
+		Scopes: a.scopes(claimStrings(lookupClaim(claims, a.opts.RolesClaim))),
+	}, nil
+}
+
+func (a *JWTAuthenticator) Challenge() string {
+	return `Bearer realm="go_swift"`
+}
+
+// Maps the caller's roles to scopes
+func (a *JWTAuthenticator) scopes(roles []string) []string {
+	if len(a.opts.RoleScopes) == 0 {
+		return roles
+	}
+
+	var scopes []string
+	for _, role := range roles {
+		scopes = append(scopes, a.opts.RoleScopes[role]...)
+	}
+	return scopes
+}
+
+// Resolves a dotted claim path such as "realm_access.roles"
+func lookupClaim(claims jwt.MapClaims, path string) any {
+	var value any = map[string]any(claims)
+	for _, part := range strings.Split(path, ".") {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = obj[part]
+	}
+	return value
+}
+
+// Converts a claim holding a string array or a space-separated string into a slice
+func claimStrings(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// Sets up a local key set and returns the signing key
+func setupLocalKeySet(t *testing.T) (*rsa.PrivateKey, *KeySet) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	assert.NoError(t, os.WriteFile(path, rsaJWKS(t, "test-key", &key.PublicKey), 0o600))
+
+	ks, err := NewFileKeySet(path)
+	assert.NoError(t, err)
+	return key, ks
+}
+
+// Signs claims with the key and returns a request carrying the token
+func bearerRequest(t *testing.T, key *rsa.PrivateKey, claims jwt.MapClaims) *http.Request {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "test-key"
+	signed, err := token.SignedString(key)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes", nil)
+	req.Header.Set("Authorization", "Bearer "+signed)
+	return req
+}
+
+// Unit test for JWTAuthenticator mapping roles to scopes
+func TestJWTAuthenticator(t *testing.T) {
+	key, ks := setupLocalKeySet(t)
+	a := NewJWTAuthenticator(ks, JWTOptions{
+		Issuer:     "https://idp.example.com",
+		Audience:   "go_swift",
+		RolesClaim: "realm_access.roles",
+		RoleScopes: map[string][]string{
+			"swift-editor": {ScopeRead, ScopeWrite},
+		},
+	})
+
+	req := bearerRequest(t, key, jwt.MapClaims{
+		"iss":                "https://idp.example.com",
+		"aud":                "go_swift",
+		"sub":                "user-1",
+		"preferred_username": "jkowalski",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"realm_access":       map[string]any{"roles": []string{"swift-editor", "offline_access"}},
+	})
+
+	principal, err := a.Authenticate(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "jwt:user-1", principal.ID)
+	assert.Equal(t, "jkowalski", principal.Name)
+	assert.Equal(t, []string{ScopeRead, ScopeWrite}, principal.Scopes)
+}
+
+// Unit test for JWTAuthenticator rejecting invalid tokens
+func TestJWTAuthenticatorInvalid(t *testing.T) {
+	key, ks := setupLocalKeySet(t)
+	a := NewJWTAuthenticator(ks, JWTOptions{Issuer: "https://idp.example.com"})
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	valid := jwt.MapClaims{"iss": "https://idp.example.com", "sub": "user-1", "exp": time.Now().Add(time.Hour).Unix()}
+	expired := jwt.MapClaims{"iss": "https://idp.example.com", "sub": "user-1", "exp": time.Now().Add(-time.Hour).Unix()}
+	wrongIssuer := jwt.MapClaims{"iss": "https://evil.example.com", "sub": "user-1", "exp": time.Now().Add(time.Hour).Unix()}
+	noExpiry := jwt.MapClaims{"iss": "https://idp.example.com", "sub": "user-1"}
+
+	for name, req := range map[string]*http.Request{
+		"expired":      bearerRequest(t, key, expired),
+		"wrong issuer": bearerRequest(t, key, wrongIssuer),
+		"no expiry":    bearerRequest(t, key, noExpiry),
+		"wrong key":    bearerRequest(t, otherKey, valid),
+	} {
+		_, err := a.Authenticate(req)
+		assert.True(t, errors.Is(err, ErrInvalidCredentials), name)
+	}
+
+	// Requests without a bearer token are left to other authenticators
+	principal, err := a.Authenticate(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.NoError(t, err)
+	assert.Nil(t, principal)
+}
+
+// Unit test for roles used as scopes when no mapping is configured
+func TestJWTAuthenticatorRolesAsScopes(t *testing.T) {
+	key, ks := setupLocalKeySet(t)
+	a := NewJWTAuthenticator(ks, JWTOptions{})
+
+	req := bearerRequest(t, key, jwt.MapClaims{
+		"sub":   "service-a",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": "codes:read codes:write",
+	})
+
+	principal, err := a.Authenticate(req)
+	assert.NoError(t, err)
+	assert.True(t, principal.HasScope(ScopeWrite))
+	assert.False(t, principal.HasScope(ScopeAdmin))
+}
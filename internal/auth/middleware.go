@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/dodskygge/go_swift/internal/respond"
+)
+
+// Public marks a rule that requires no authentication.
+const Public = ""
+
+// Rule requires a scope for requests with the given method to a route pattern.
+// Method "*" matches every method.
+type Rule struct {
+	Method string
+	Route  string
+	Scope  string
+}
+
+// Policy maps routes to the scope they require.
+type Policy []Rule
+
+// Required returns the scope required for a method and route pattern.
+// Routes without a matching rule require the admin scope, so new routes are never exposed by accident.
+func (p Policy) Required(method, route string) string {
+	for _, rule := range p {
+		if rule.Route == route && (rule.Method == "*" || rule.Method == method) {
+			return rule.Scope
+		}
+	}
+	return ScopeAdmin
+}
+
+// Middleware authenticates requests with the given authenticators and enforces the policy.
+// Unmatched routes are passed through so the mux can answer 404.
+func Middleware(policy Policy, route func(*http.Request) string, authenticators ...Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pattern := route(r)
+			if pattern == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			principal, err := Authenticate(r, authenticators...)
+			if err != nil && !errors.Is(err, ErrInvalidCredentials) {
+				slog.ErrorContext(r.Context(), "authentication failed", "error", err)
+				respond.Error(w, r, http.StatusInternalServerError, "Authentication failed")
+				return
+			}
+
+			scope := policy.Required(r.Method, pattern)
+			if scope != Public {
+				if principal == nil {
+					for _, a := range authenticators {
+						if challenge := a.Challenge(); challenge != "" {
+							w.Header().Add("WWW-Authenticate", challenge)
+						}
+					}
+					respond.Error(w, r, http.StatusUnauthorized, "Unauthorized")
+					return
+				}
+				if !principal.HasScope(scope) {
+					slog.WarnContext(r.Context(), "insufficient scope", "principal", principal.ID, "required_scope", scope)
+					respond.Error(w, r, http.StatusForbidden, "Forbidden")
+					return
+				}
+			}
+
+			if principal != nil {
+				r = r.WithContext(WithPrincipal(r.Context(), principal))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Authenticate returns the principal from the first authenticator that finds credentials in the request,
+// or nil when the request carries none.
+func Authenticate(r *http.Request, authenticators ...Authenticator) (*Principal, error) {
+	for _, a := range authenticators {
+		principal, err := a.Authenticate(r)
+		if err != nil {
+			return nil, err
+		}
+		if principal != nil {
+			return principal, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Unit test for Policy.Required
+func TestPolicyRequired(t *testing.T) {
+	policy := Policy{
+		{Method: "*", Route: "/api/v1/health", Scope: Public},
+		{Method: http.MethodGet, Route: "/api/v1/swift-codes/", Scope: ScopeRead},
+		{Method: http.MethodDelete, Route: "/api/v1/swift-codes/", Scope: ScopeWrite},
+	}
+
+	assert.Equal(t, Public, policy.Required(http.MethodGet, "/api/v1/health"))
+	assert.Equal(t, ScopeRead, policy.Required(http.MethodGet, "/api/v1/swift-codes/"))
+	assert.Equal(t, ScopeWrite, policy.Required(http.MethodDelete, "/api/v1/swift-codes/"))
+	assert.Equal(t, ScopeAdmin, policy.Required(http.MethodPut, "/api/v1/swift-codes/"))
+	assert.Equal(t, ScopeAdmin, policy.Required(http.MethodGet, "/api/v1/unlisted"))
+}
+
+// Unit test for Middleware enforcing scopes per route
+func TestMiddleware(t *testing.T) {
+	store := &memoryKeyStore{}
+	readKey, _, _ := IssueAPIKey(context.Background(), store, "reader", []string{ScopeRead})
+
+	mux := http.NewServeMux()
+	var seen *Principal
+	ok := func(w http.ResponseWriter, r *http.Request) { seen = PrincipalFromContext(r.Context()) }
+	mux.HandleFunc("/api/v1/health", ok)
+	mux.HandleFunc("/api/v1/swift-codes/", ok)
+
+	policy := Policy{
+		{Method: "*", Route: "/api/v1/health", Scope: Public},
+		{Method: http.MethodGet, Route: "/api/v1/swift-codes/", Scope: ScopeRead},
+		{Method: http.MethodDelete, Route: "/api/v1/swift-codes/", Scope: ScopeWrite},
+	}
+	route := func(r *http.Request) string { _, p := mux.Handler(r); return p }
+	h := Middleware(policy, route, &APIKeyAuthenticator{Store: store})(mux)
+
+	do := func(method, path, key string) int {
+		req := httptest.NewRequest(method, path, nil)
+		if key != "" {
+			req.Header.Set(APIKeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v1/health", ""))
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/api/v1/swift-codes/TESTUS33XXX", ""))
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/api/v1/swift-codes/TESTUS33XXX", "gsk_wrong"))
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v1/swift-codes/TESTUS33XXX", readKey))
+	assert.Equal(t, "reader", seen.Name)
+	assert.Equal(t, http.StatusForbidden, do(http.MethodDelete, "/api/v1/swift-codes/TESTUS33XXX", readKey))
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/unknown", ""))
+
+	// Errors follow the Accept header like those of the handlers
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/TESTUS33XXX", nil)
+	req.Header.Set("Accept", "application/xml")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "application/xml", rec.Header().Get("Content-Type"))
+}
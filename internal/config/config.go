@@ -0,0 +1,290 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the runtime configuration of the API server.
+type Config struct {
+	Port string
+	// GRPCPort is the port of the gRPC server, "off" disables it
+	GRPCPort  string
+	LogFormat string
+	LogLevel  string
+	CacheTTL  time.Duration
+
+	// BatchMaxCodes is the number of codes accepted by a batch lookup.
+	BatchMaxCodes int
+
+	// IdempotencyKeyTTL is how long responses to requests with an Idempotency-Key are kept for replay.
+	IdempotencyKeyTTL time.Duration
+
+	// CacheControl maps route patterns to the Cache-Control header of their GET responses.
+	CacheControl map[string]string
+
+	TraceExporter string
+	TraceFile     string
+	ServiceName   string
+
+	// AuthMethods lists the enabled authentication methods, apikey unless set; authentication is only
+	// disabled, leaving it empty, with an explicit AUTH_METHODS=none.
+	AuthMethods []string
+
+	JWTJWKSFile    string
+	JWTJWKSURL     string
+	JWTJWKSRefresh time.Duration
+	JWTIssuer      string
+	JWTAudience    string
+	JWTRolesClaim  string
+	JWTRoleScopes  map[string][]string
+
+	// RateLimit applies per client to every route without an entry in RateLimitRoutes.
+	RateLimit           RateLimit
+	RateLimitRoutes     map[string]RateLimit
+	RateLimitTrustProxy bool
+
+	// TLS is enabled when TLSCertFile and TLSKeyFile are set.
+	TLSCertFile     string
+	TLSKeyFile      string
+	TLSClientCAFile string
+	// TLSClientAuth is none, optional or require; it defaults to require when TLSClientCAFile is set.
+	TLSClientAuth string
+	// TLSClientSubjectScopes maps client certificate subjects to scopes for mtls authentication.
+	TLSClientSubjectScopes map[string][]string
+
+	// CORS is enabled when CORSAllowedOrigins is not empty.
+	CORSAllowedOrigins   []string
+	CORSAllowedMethods   []string
+	CORSAllowedHeaders   []string
+	CORSExposedHeaders   []string
+	CORSMaxAge           time.Duration
+	CORSAllowCredentials bool
+}
+
+// RateLimit is a token bucket refilled at RPS requests per second with room for Burst requests.
+type RateLimit struct {
+	RPS   float64 `json:"rps"`
+	Burst int     `json:"burst"`
+}
+
+// Load reads the configuration from environment variables, applying defaults where a value is not set.
+func Load() (*Config, error) {
+	cfg := &Config{
+		Port:      getEnv("PORT", "8080"),
+		GRPCPort:  strings.ToLower(getEnv("GRPC_PORT", "9090")),
+		LogFormat: strings.ToLower(getEnv("LOG_FORMAT", "json")),
+		LogLevel:  getEnv("LOG_LEVEL", "info"),
+
+		TraceExporter: strings.ToLower(getEnv("OTEL_TRACES_EXPORTER", "none")),
+		TraceFile:     getEnv("OTEL_TRACES_FILE", "traces.json"),
+		ServiceName:   getEnv("OTEL_SERVICE_NAME", "go_swift"),
+
+		AuthMethods: getList("AUTH_METHODS", nil),
+
+		JWTJWKSFile:   os.Getenv("JWT_JWKS_FILE"),
+		JWTJWKSURL:    os.Getenv("JWT_JWKS_URL"),
+		JWTIssuer:     os.Getenv("JWT_ISSUER"),
+		JWTAudience:   os.Getenv("JWT_AUDIENCE"),
+		JWTRolesClaim: getEnv("JWT_ROLES_CLAIM", "roles"),
+
+		TLSCertFile:     os.Getenv("TLS_CERT_FILE"),
+		TLSKeyFile:      os.Getenv("TLS_KEY_FILE"),
+		TLSClientCAFile: os.Getenv("TLS_CLIENT_CA_FILE"),
+		TLSClientAuth:   strings.ToLower(os.Getenv("TLS_CLIENT_AUTH")),
+
+		CORSAllowedOrigins:   getList("CORS_ALLOWED_ORIGINS", nil),
+		CORSAllowedMethods:   getList("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE"}),
+		CORSAllowedHeaders:   getList("CORS_ALLOWED_HEADERS", []string{"Content-Type", "Authorization", "X-API-Key", "X-Request-ID", "If-Match", "If-None-Match", "If-Modified-Since", "Idempotency-Key"}),
+		CORSExposedHeaders:   getList("CORS_EXPOSED_HEADERS", []string{"X-Request-ID", "ETag", "Last-Modified", "Idempotent-Replayed", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"}),
+		CORSAllowCredentials: os.Getenv("CORS_ALLOW_CREDENTIALS") == "true",
+	}
+
+	if cfg.GRPCPort != "off" {
+		if port, err := strconv.Atoi(cfg.GRPCPort); err != nil || port < 1 || port > 65535 {
+			return nil, fmt.Errorf("invalid GRPC_PORT %q: must be a port number or off", cfg.GRPCPort)
+		}
+	}
+
+	if cfg.LogFormat != "json" && cfg.LogFormat != "text" {
+		return nil, fmt.Errorf("invalid LOG_FORMAT %q: must be json or text", cfg.LogFormat)
+	}
+
+	switch {
+	case len(cfg.AuthMethods) == 0:
+		cfg.AuthMethods = []string{"apikey"}
+	case len(cfg.AuthMethods) == 1 && strings.EqualFold(cfg.AuthMethods[0], "none"):
+		cfg.AuthMethods = nil
+	}
+	for i, method := range cfg.AuthMethods {
+		method = strings.ToLower(method)
+		cfg.AuthMethods[i] = method
+		if method != "apikey" && method != "jwt" && method != "mtls" {
+			return nil, fmt.Errorf("invalid AUTH_METHODS entry %q: must be apikey, jwt or mtls, or none alone", method)
+		}
+	}
+	if slices.Contains(cfg.AuthMethods, "jwt") {
+		if cfg.JWTJWKSFile == "" && cfg.JWTJWKSURL == "" {
+			return nil, fmt.Errorf("JWT_JWKS_FILE or JWT_JWKS_URL is required for jwt authentication")
+		}
+		// Without them, tokens the identity provider issued for other services would be accepted
+		if cfg.JWTIssuer == "" || cfg.JWTAudience == "" {
+			return nil, fmt.Errorf("JWT_ISSUER and JWT_AUDIENCE are required for jwt authentication")
+		}
+	}
+
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		return nil, fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if cfg.TLSClientAuth == "" {
+		cfg.TLSClientAuth = "none"
+		if cfg.TLSClientCAFile != "" {
+			cfg.TLSClientAuth = "require"
+		}
+	}
+	if !slices.Contains([]string{"none", "optional", "require"}, cfg.TLSClientAuth) {
+		return nil, fmt.Errorf("invalid TLS_CLIENT_AUTH %q: must be none, optional or require", cfg.TLSClientAuth)
+	}
+	if cfg.TLSClientAuth != "none" && (cfg.TLSCertFile == "" || cfg.TLSClientCAFile == "") {
+		return nil, fmt.Errorf("TLS_CLIENT_AUTH %s requires TLS_CERT_FILE, TLS_KEY_FILE and TLS_CLIENT_CA_FILE", cfg.TLSClientAuth)
+	}
+	if slices.Contains(cfg.AuthMethods, "mtls") && cfg.TLSClientAuth == "none" {
+		return nil, fmt.Errorf("mtls authentication requires TLS_CLIENT_CA_FILE")
+	}
+
+	var err error
+	if cfg.CacheTTL, err = getDuration("CACHE_TTL", 0); err != nil {
+		return nil, err
+	}
+	// MySQL allows at most 65535 placeholders per statement
+	batchMaxCodes := getEnv("BATCH_MAX_CODES", "1000")
+	if cfg.BatchMaxCodes, err = strconv.Atoi(batchMaxCodes); err != nil || cfg.BatchMaxCodes < 1 || cfg.BatchMaxCodes > 10000 {
+		return nil, fmt.Errorf("invalid BATCH_MAX_CODES %q: must be an integer between 1 and 10000", batchMaxCodes)
+	}
+
+	if cfg.IdempotencyKeyTTL, err = getDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour); err != nil {
+		return nil, err
+	}
+	cfg.CacheControl = map[string]string{
+		"/api/v1/swift-codes/":         "private, no-cache",
+		"/api/v1/swift-codes/country/": "private, no-cache",
+		"/api/v1/institutions/":        "private, no-cache",
+	}
+	if value := os.Getenv("CACHE_CONTROL_ROUTES"); value != "" {
+		var routes map[string]string
+		if err := json.Unmarshal([]byte(value), &routes); err != nil {
+			return nil, fmt.Errorf("invalid CACHE_CONTROL_ROUTES: %w", err)
+		}
+		for route, policy := range routes {
+			cfg.CacheControl[route] = policy
+		}
+	}
+	if cfg.JWTJWKSRefresh, err = getDuration("JWT_JWKS_REFRESH", time.Hour); err != nil {
+		return nil, err
+	}
+	if value := os.Getenv("JWT_ROLE_SCOPES"); value != "" {
+		if err := json.Unmarshal([]byte(value), &cfg.JWTRoleScopes); err != nil {
+			return nil, fmt.Errorf("invalid JWT_ROLE_SCOPES: %w", err)
+		}
+	}
+
+	if value := os.Getenv("TLS_CLIENT_SUBJECT_SCOPES"); value != "" {
+		if err := json.Unmarshal([]byte(value), &cfg.TLSClientSubjectScopes); err != nil {
+			return nil, fmt.Errorf("invalid TLS_CLIENT_SUBJECT_SCOPES: %w", err)
+		}
+	}
+
+	if cfg.RateLimit.RPS, err = getFloat("RATE_LIMIT_RPS", 0); err != nil {
+		return nil, err
+	}
+	burst, err := getFloat("RATE_LIMIT_BURST", 0)
+	if err != nil {
+		return nil, err
+	}
+	cfg.RateLimit.Burst = int(burst)
+	cfg.RateLimit = cfg.RateLimit.withDefaultBurst()
+
+	if value := os.Getenv("RATE_LIMIT_ROUTES"); value != "" {
+		if err := json.Unmarshal([]byte(value), &cfg.RateLimitRoutes); err != nil {
+			return nil, fmt.Errorf("invalid RATE_LIMIT_ROUTES: %w", err)
+		}
+		for route, limit := range cfg.RateLimitRoutes {
+			cfg.RateLimitRoutes[route] = limit.withDefaultBurst()
+		}
+	}
+	cfg.RateLimitTrustProxy = os.Getenv("RATE_LIMIT_TRUST_FORWARDED_FOR") == "true"
+
+	if cfg.CORSMaxAge, err = getDuration("CORS_MAX_AGE", 10*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.CORSAllowCredentials && slices.Contains(cfg.CORSAllowedOrigins, "*") {
+		return nil, fmt.Errorf("CORS_ALLOWED_ORIGINS cannot be * when CORS_ALLOW_CREDENTIALS is true")
+	}
+
+	return cfg, nil
+}
+
+// Returns the value of the environment variable or the default if it is empty
+func getEnv(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
+
+// Splits a comma-separated environment variable into trimmed, non-empty values,
+// returning the default when the variable is not set
+func getList(key string, def []string) []string {
+	raw, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+
+	var values []string
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Defaults the burst to one second worth of requests
+func (l RateLimit) withDefaultBurst() RateLimit {
+	if l.RPS > 0 && l.Burst <= 0 {
+		l.Burst = int(math.Max(1, math.Ceil(l.RPS)))
+	}
+	return l
+}
+
+// Parses a number from the environment variable or returns the default if it is empty
+func getFloat(key string, def float64) (float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a non-negative number", key, value)
+	}
+	return f, nil
+}
+
+// Parses a duration such as "30s" from the environment variable or returns the default if it is empty
+func getDuration(key string, def time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", key, value, err)
+	}
+	return d, nil
+}
@@ -0,0 +1,218 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Unit test for Load with defaults
+func TestLoadDefaults(t *testing.T) {
+	t.Setenv("PORT", "")
+	t.Setenv("LOG_FORMAT", "")
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("CACHE_TTL", "")
+	t.Setenv("AUTH_METHODS", "")
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "8080", cfg.Port)
+	assert.Equal(t, "json", cfg.LogFormat)
+	assert.Equal(t, "info", cfg.LogLevel)
+	assert.Zero(t, cfg.CacheTTL)
+	assert.Equal(t, []string{"apikey"}, cfg.AuthMethods)
+}
+
+// Unit test for Load with an invalid log format
+func TestLoadInvalidLogFormat(t *testing.T) {
+	t.Setenv("LOG_FORMAT", "xml")
+
+	_, err := Load()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "LOG_FORMAT")
+}
+
+// Unit test for Load with an invalid duration
+func TestLoadInvalidDuration(t *testing.T) {
+	t.Setenv("CACHE_TTL", "soon")
+
+	_, err := Load()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "CACHE_TTL")
+}
+
+// Unit test for Load with authentication methods
+func TestLoadAuthMethods(t *testing.T) {
+	t.Setenv("AUTH_METHODS", " ApiKey, ")
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"apikey"}, cfg.AuthMethods)
+
+	t.Setenv("AUTH_METHODS", "basic")
+	_, err = Load()
+	assert.Error(t, err)
+
+	// Authentication is only disabled explicitly
+	t.Setenv("AUTH_METHODS", "None")
+	cfg, err = Load()
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.AuthMethods)
+
+	t.Setenv("AUTH_METHODS", "none,apikey")
+	_, err = Load()
+	assert.Error(t, err)
+}
+
+// Unit test for Load with JWT settings
+func TestLoadJWT(t *testing.T) {
+	t.Setenv("AUTH_METHODS", "apikey,jwt")
+	t.Setenv("JWT_JWKS_FILE", "")
+	t.Setenv("JWT_JWKS_URL", "")
+
+	// A key set source is required
+	_, err := Load()
+	assert.Error(t, err)
+
+	t.Setenv("JWT_JWKS_URL", "https://idp.example.com/jwks.json")
+	t.Setenv("JWT_ROLE_SCOPES", `{"swift-editor":["codes:read","codes:write"]}`)
+	t.Setenv("JWT_ISSUER", "https://idp.example.com")
+	t.Setenv("JWT_AUDIENCE", "")
+
+	// The issuer and audience are required
+	_, err = Load()
+	assert.ErrorContains(t, err, "JWT_AUDIENCE")
+
+	t.Setenv("JWT_AUDIENCE", "go_swift")
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"codes:read", "codes:write"}, cfg.JWTRoleScopes["swift-editor"])
+	assert.Equal(t, "roles", cfg.JWTRolesClaim)
+
+	t.Setenv("JWT_ROLE_SCOPES", `swift-editor=codes:write`)
+	_, err = Load()
+	assert.Error(t, err)
+}
+
+// Unit test for Load with rate limits
+func TestLoadRateLimit(t *testing.T) {
+	t.Setenv("RATE_LIMIT_RPS", "2.5")
+	t.Setenv("RATE_LIMIT_BURST", "")
+	t.Setenv("RATE_LIMIT_ROUTES", `{"/api/v1/swift-codes/":{"rps":50,"burst":100},"/api/v1/swift-codes":{"rps":1}}`)
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, RateLimit{RPS: 2.5, Burst: 3}, cfg.RateLimit)
+	assert.Equal(t, RateLimit{RPS: 50, Burst: 100}, cfg.RateLimitRoutes["/api/v1/swift-codes/"])
+	assert.Equal(t, RateLimit{RPS: 1, Burst: 1}, cfg.RateLimitRoutes["/api/v1/swift-codes"])
+
+	t.Setenv("RATE_LIMIT_RPS", "-1")
+	_, err = Load()
+	assert.Error(t, err)
+}
+
+// Unit test for Load with a batch size limit
+func TestLoadBatchMaxCodes(t *testing.T) {
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, cfg.BatchMaxCodes)
+
+	t.Setenv("BATCH_MAX_CODES", "50000")
+	_, err = Load()
+	assert.Error(t, err)
+
+	// Fractions are rejected rather than truncated
+	t.Setenv("BATCH_MAX_CODES", "0.5")
+	_, err = Load()
+	assert.ErrorContains(t, err, "BATCH_MAX_CODES")
+
+	t.Setenv("BATCH_MAX_CODES", "250")
+	cfg, err = Load()
+	assert.NoError(t, err)
+	assert.Equal(t, 250, cfg.BatchMaxCodes)
+}
+
+// Unit test for Load with a gRPC port
+func TestLoadGRPCPort(t *testing.T) {
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "9090", cfg.GRPCPort)
+
+	t.Setenv("GRPC_PORT", "OFF")
+	cfg, err = Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "off", cfg.GRPCPort)
+
+	t.Setenv("GRPC_PORT", "grpc")
+	_, err = Load()
+	assert.Error(t, err)
+}
+
+// Unit test for Load with an idempotency key TTL
+func TestLoadIdempotencyKeyTTL(t *testing.T) {
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, cfg.IdempotencyKeyTTL)
+
+	t.Setenv("IDEMPOTENCY_KEY_TTL", "1h")
+	cfg, err = Load()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, cfg.IdempotencyKeyTTL)
+}
+
+// Unit test for Load with Cache-Control overrides
+func TestLoadCacheControl(t *testing.T) {
+	t.Setenv("CACHE_CONTROL_ROUTES", `{"/api/v1/swift-codes/country/":"public, max-age=300","/api/v1/swift-codes/":""}`)
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "public, max-age=300", cfg.CacheControl["/api/v1/swift-codes/country/"])
+	assert.Empty(t, cfg.CacheControl["/api/v1/swift-codes/"])
+
+	t.Setenv("CACHE_CONTROL_ROUTES", `{"/api/v1/swift-codes/":60}`)
+	_, err = Load()
+	assert.Error(t, err)
+}
+
+// Unit test for Load with CORS settings
+func TestLoadCORS(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com, https://*.partners.example.com")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://app.example.com", "https://*.partners.example.com"}, cfg.CORSAllowedOrigins)
+	assert.Contains(t, cfg.CORSAllowedMethods, "DELETE")
+	assert.Equal(t, 10*time.Minute, cfg.CORSMaxAge)
+
+	// Credentials cannot be combined with any origin
+	t.Setenv("CORS_ALLOWED_ORIGINS", "*")
+	_, err = Load()
+	assert.Error(t, err)
+}
+
+// Unit test for Load with TLS settings
+func TestLoadTLS(t *testing.T) {
+	t.Setenv("TLS_CERT_FILE", "/etc/go_swift/tls.crt")
+	t.Setenv("TLS_KEY_FILE", "/etc/go_swift/tls.key")
+	t.Setenv("TLS_CLIENT_CA_FILE", "/etc/go_swift/clients-ca.crt")
+	t.Setenv("AUTH_METHODS", "mtls")
+	t.Setenv("TLS_CLIENT_SUBJECT_SCOPES", `{"reconciliation":["codes:read"]}`)
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "require", cfg.TLSClientAuth)
+	assert.Equal(t, []string{"codes:read"}, cfg.TLSClientSubjectScopes["reconciliation"])
+
+	// A client CA without a server certificate
+	t.Setenv("TLS_CERT_FILE", "")
+	_, err = Load()
+	assert.Error(t, err)
+
+	// mtls authentication without client certificate verification
+	t.Setenv("TLS_CERT_FILE", "/etc/go_swift/tls.crt")
+	t.Setenv("TLS_CLIENT_AUTH", "none")
+	_, err = Load()
+	assert.Error(t, err)
+}
@@ -4,10 +4,16 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"strings"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/dodskygge/go_swift/internal/tlsutil"
+	"github.com/go-sql-driver/mysql"
 )
 
+// tlsConfigName is the name under which a custom TLS configuration is registered with the MySQL driver.
+const tlsConfigName = "go_swift"
+
+// ConnectDB opens the MySQL connection configured by the DB_* environment variables.
 func ConnectDB() (*sql.DB, error) {
 	user := os.Getenv("DB_USER")
 	password := os.Getenv("DB_PASS")
@@ -28,6 +34,15 @@ func ConnectDB() (*sql.DB, error) {
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&charset=utf8mb4",
 		user, password, host, port, dbname)
 
+	// Encrypt the connection when DB_TLS or a CA/client certificate is configured
+	tlsParam, err := tlsMode()
+	if err != nil {
+		return nil, err
+	}
+	if tlsParam != "" {
+		dsn += "&tls=" + tlsParam
+	}
+
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
 		return nil, err
@@ -40,3 +55,37 @@ func ConnectDB() (*sql.DB, error) {
 
 	return db, nil
 }
+
+// Returns the tls DSN parameter for DB_TLS (true, false, skip-verify or preferred), registering a custom
+// configuration when DB_TLS_CA or DB_TLS_CERT/DB_TLS_KEY are set; the files alone enable TLS as well
+func tlsMode() (string, error) {
+	mode := strings.ToLower(os.Getenv("DB_TLS"))
+	caFile := os.Getenv("DB_TLS_CA")
+	certFile := os.Getenv("DB_TLS_CERT")
+	keyFile := os.Getenv("DB_TLS_KEY")
+
+	switch mode {
+	case "false":
+		return "", nil
+	case "":
+		if caFile == "" && certFile == "" && keyFile == "" {
+			return "", nil
+		}
+	case "true", "skip-verify", "preferred":
+		if caFile == "" && certFile == "" && keyFile == "" {
+			return mode, nil
+		}
+	default:
+		return "", fmt.Errorf("invalid DB_TLS %q: must be true, false, skip-verify or preferred", mode)
+	}
+
+	cfg, err := tlsutil.ClientConfig(caFile, certFile, keyFile)
+	if err != nil {
+		return "", fmt.Errorf("invalid database TLS configuration: %w", err)
+	}
+	cfg.InsecureSkipVerify = mode == "skip-verify"
+	if err := mysql.RegisterTLSConfig(tlsConfigName, cfg); err != nil {
+		return "", err
+	}
+	return tlsConfigName, nil
+}
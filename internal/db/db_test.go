@@ -2,6 +2,7 @@ package db
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -23,3 +24,26 @@ func TestConnectDB(t *testing.T) {
 		database.Close()
 	}
 }
+
+// Unit test for tlsMode
+func TestTLSMode(t *testing.T) {
+	t.Setenv("DB_TLS", "")
+	mode, err := tlsMode()
+	assert.NoError(t, err)
+	assert.Empty(t, mode)
+
+	t.Setenv("DB_TLS", "skip-verify")
+	mode, err = tlsMode()
+	assert.NoError(t, err)
+	assert.Equal(t, "skip-verify", mode)
+
+	t.Setenv("DB_TLS", "always")
+	_, err = tlsMode()
+	assert.Error(t, err)
+
+	// A CA file that does not exist
+	t.Setenv("DB_TLS", "true")
+	t.Setenv("DB_TLS_CA", filepath.Join(t.TempDir(), "ca.pem"))
+	_, err = tlsMode()
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+)
+
+// Unit test for migrateFS applying each migration exactly once
+func TestMigrateFS(t *testing.T) {
+	database, err := sql.Open("sqlite", ":memory:")
+	assert.NoError(t, err)
+	defer database.Close()
+
+	fsys := fstest.MapFS{
+		"001_create_items.sql": {Data: []byte(`
+-- Items table
+CREATE TABLE items (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL
+);
+INSERT INTO items (id, name) VALUES (1, 'first');
+`)},
+		"002_add_item.sql": {Data: []byte(`INSERT INTO items (id, name) VALUES (2, 'second');`)},
+	}
+
+	assert.NoError(t, migrateFS(context.Background(), database, fsys))
+	// Running again must not re-apply anything
+	assert.NoError(t, migrateFS(context.Background(), database, fsys))
+
+	var count int
+	assert.NoError(t, database.QueryRow(`SELECT COUNT(*) FROM items`).Scan(&count))
+	assert.Equal(t, 2, count)
+
+	assert.NoError(t, database.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&count))
+	assert.Equal(t, 2, count)
+}
+
+// Unit test for splitStatements
+func TestSplitStatements(t *testing.T) {
+	statements := splitStatements(`
+-- comment
+CREATE TABLE a (
+  id INT
+);
+INSERT INTO a VALUES (1);
+`)
+	assert.Equal(t, []string{"CREATE TABLE a (\n  id INT\n)", "INSERT INTO a VALUES (1)"}, statements)
+}
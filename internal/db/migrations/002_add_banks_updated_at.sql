@@ -0,0 +1,3 @@
+-- Last modification time of each SWIFT code, used for Last-Modified and conditional requests
+ALTER TABLE `banks`
+  ADD COLUMN `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;
@@ -0,0 +1,3 @@
+-- Version of each SWIFT code, incremented on every update for optimistic concurrency control
+ALTER TABLE `banks`
+  ADD COLUMN `version` int(11) NOT NULL DEFAULT 1;
@@ -0,0 +1,15 @@
+-- Responses of requests sent with an Idempotency-Key header, replayed when the request is retried; keys and
+-- principals are compared byte for byte, so keys differing only in case or accents are distinct
+CREATE TABLE IF NOT EXISTS `idempotency_keys` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `principal` varchar(100) COLLATE utf8mb4_bin NOT NULL,
+  `idempotency_key` varchar(255) COLLATE utf8mb4_bin NOT NULL,
+  `request_hash` char(64) NOT NULL,
+  `status_code` int(11) NOT NULL DEFAULT 0,
+  `content_type` varchar(100) NOT NULL DEFAULT '',
+  `response_body` mediumblob DEFAULT NULL,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `principal_key` (`principal`, `idempotency_key`),
+  KEY `created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
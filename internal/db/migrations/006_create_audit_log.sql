@@ -0,0 +1,16 @@
+-- Every change of a SWIFT code with the principal that made it and the record before and after it as JSON,
+-- written in the same transaction as the change
+CREATE TABLE IF NOT EXISTS `audit_log` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `actor` varchar(100) NOT NULL,
+  `action` varchar(20) NOT NULL,
+  `swift_code` varchar(20) NOT NULL,
+  `before_data` text DEFAULT NULL,
+  `after_data` text DEFAULT NULL,
+  `request_id` varchar(100) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`),
+  KEY `swift_code` (`swift_code`, `id`),
+  KEY `actor` (`actor`, `id`),
+  KEY `created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
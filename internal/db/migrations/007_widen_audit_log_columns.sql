@@ -0,0 +1,5 @@
+-- Room for the longest principal IDs, such as token subjects and certificate names, and for request IDs up to
+-- logging.MaxRequestIDLength
+ALTER TABLE `audit_log`
+  MODIFY COLUMN `actor` varchar(255) NOT NULL,
+  MODIFY COLUMN `request_id` varchar(128) NOT NULL DEFAULT '';
@@ -0,0 +1,86 @@
+// Package graphqlapi serves GraphQL queries over the SWIFT codes, resolved by the same service as the REST handlers.
+package graphqlapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"github.com/dodskygge/go_swift/internal/service"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// Limits protecting the service from expensive queries
+const (
+	maxDepth     = 8
+	maxBodyBytes = 1 << 20
+)
+
+// Body of a GraphQL request
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Handler executes GraphQL queries sent as JSON in POST requests.
+type Handler struct {
+	schema *graphql.Schema
+}
+
+// NewHandler returns a handler resolving queries with svc. Branches of the headquarters listed for a country
+// are loaded with one repository query for the whole list rather than one per headquarters.
+func NewHandler(svc *service.SwiftCodeService) *Handler {
+	schema := graphql.MustParseSchema(schemaSDL, &resolver{svc: svc},
+		graphql.UseStringDescriptions(),
+		graphql.MaxDepth(maxDepth),
+	)
+	return &Handler{schema: schema}
+}
+
+// ServeHTTP handles POST /api/v1/graphql. Query errors are reported in the errors list of a 200 response,
+// malformed requests get 400 Bad Request.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeErrors(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(&req); err != nil {
+		writeErrors(w, http.StatusBadRequest, "Invalid request data")
+		return
+	}
+	if req.Query == "" {
+		writeErrors(w, http.StatusBadRequest, "query is required")
+		return
+	}
+
+	response := h.schema.Exec(r.Context(), req.Query, req.OperationName, req.Variables)
+	data, err := json.Marshal(response)
+	if err != nil {
+		writeErrors(w, http.StatusInternalServerError, "Error encoding response")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(data, '\n'))
+}
+
+// Writes a response carrying only errors, in the GraphQL response format
+func writeErrors(w http.ResponseWriter, status int, message string) {
+	type graphqlError struct {
+		Message string `json:"message"`
+	}
+	data, _ := json.Marshal(struct {
+		Errors []graphqlError `json:"errors"`
+	}{Errors: []graphqlError{{Message: message}}})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+}
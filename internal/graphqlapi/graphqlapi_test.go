@@ -0,0 +1,230 @@
+package graphqlapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/dodskygge/go_swift/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock repository for testing
+type MockSwiftCodeRepository struct {
+	mock.Mock
+}
+
+func (m *MockSwiftCodeRepository) GetBySwiftCode(ctx context.Context, code string) (*model.SwiftEntity, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SwiftEntity), args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) GetBranchesByHqSwiftCode(ctx context.Context, hqCode string) ([]*model.SwiftEntity, error) {
+	args := m.Called(ctx, hqCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SwiftEntity), args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) GetBranchesByHqSwiftCodes(ctx context.Context, hqCodes []string) ([]*model.SwiftEntity, error) {
+	args := m.Called(ctx, hqCodes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SwiftEntity), args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) GetByCountry(ctx context.Context, countryISO2 string) ([]*model.SwiftEntity, error) {
+	args := m.Called(ctx, countryISO2)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SwiftEntity), args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) GetBySwiftCodes(ctx context.Context, swiftCodes []string) ([]*model.SwiftEntity, error) {
+	args := m.Called(ctx, swiftCodes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SwiftEntity), args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) Search(ctx context.Context, search model.SwiftCodeSearch) ([]*model.SwiftEntity, error) {
+	args := m.Called(ctx, search)
+	entities, _ := args.Get(0).([]*model.SwiftEntity)
+	return entities, args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) ForEach(ctx context.Context, filter model.SwiftCodeFilter, fn func(*model.SwiftEntity) error) error {
+	args := m.Called(ctx, filter, fn)
+	if entities, ok := args.Get(0).([]*model.SwiftEntity); ok {
+		for _, entity := range entities {
+			if err := fn(entity); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) Create(ctx context.Context, swift *model.SwiftEntity) error {
+	args := m.Called(ctx, swift)
+	return args.Error(0)
+}
+
+func (m *MockSwiftCodeRepository) BulkWrite(ctx context.Context, entities []*model.SwiftEntity, upsert, atomic bool) ([]model.BulkItemResult, bool, error) {
+	args := m.Called(ctx, entities, upsert, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).([]model.BulkItemResult), args.Bool(1), args.Error(2)
+}
+
+func (m *MockSwiftCodeRepository) Update(ctx context.Context, swift *model.SwiftEntity, expectedVersion int64) error {
+	args := m.Called(ctx, swift, expectedVersion)
+	return args.Error(0)
+}
+
+func (m *MockSwiftCodeRepository) Delete(ctx context.Context, swiftCode string, expectedVersion int64) error {
+	args := m.Called(ctx, swiftCode, expectedVersion)
+	return args.Error(0)
+}
+
+func (m *MockSwiftCodeRepository) ListCountries(ctx context.Context) ([]*model.Country, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Country), args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) Stats(ctx context.Context, countryISO2 string) (*model.SwiftCodeStats, error) {
+	args := m.Called(ctx, countryISO2)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SwiftCodeStats), args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) GetAuditLog(ctx context.Context, query model.AuditQuery) ([]model.AuditEntry, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AuditEntry), args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) GetByInstitution(ctx context.Context, bic4 string) ([]*model.SwiftEntity, error) {
+	args := m.Called(ctx, bic4)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SwiftEntity), args.Error(1)
+}
+
+// Sends a GraphQL query and decodes the response
+func query(t *testing.T, repo service.SwiftCodeRepository, body string) (int, map[string]any) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/graphql", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+	NewHandler(service.NewSwiftCodeService(repo)).ServeHTTP(rr, req)
+
+	var response map[string]any
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return rr.Code, response
+}
+
+// Unit test for a country with its headquarters and their branches
+func TestCountryQuery(t *testing.T) {
+	mockRepo := new(MockSwiftCodeRepository)
+
+	mockRepo.On("GetByCountry", mock.Anything, "PL").Return([]*model.SwiftEntity{
+		{SwiftCode: "TESTPLPWXXX", BankName: "Test Bank", Address: "1 Prosta", CountryISO2: "PL", CountryName: "Poland", IsHeadquarter: true},
+		{SwiftCode: "TESTPLPWKRK", BankName: "Test Bank", Address: "2 Krakowska", CountryISO2: "PL", CountryName: "Poland"},
+		{SwiftCode: "OTHRPLPWXXX", BankName: "Other Bank", Address: "3 Długa", CountryISO2: "PL", CountryName: "Poland", IsHeadquarter: true},
+	}, nil)
+	// Branches of both headquarters are loaded with a single repository call
+	mockRepo.On("GetBranchesByHqSwiftCodes", mock.Anything, mock.MatchedBy(func(prefixes []string) bool {
+		return assert.ElementsMatch(t, []string{"TESTPLPW", "OTHRPLPW"}, prefixes)
+	})).Return([]*model.SwiftEntity{
+		{SwiftCode: "TESTPLPWKRK", BankName: "Test Bank", Address: "2 Krakowska", CountryISO2: "pl"},
+	}, nil).Once()
+
+	status, response := query(t, mockRepo, `{"query":"query($iso2: String!) { country(iso2: $iso2) { name banks(isHeadquarter: true) { swiftCode countryName branches { swiftCode countryISO2 } } } }","variables":{"iso2":"PL"}}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Nil(t, response["errors"])
+	assert.Equal(t, map[string]any{"country": map[string]any{
+		"name": "POLAND",
+		"banks": []any{
+			map[string]any{"swiftCode": "TESTPLPWXXX", "countryName": "POLAND", "branches": []any{
+				map[string]any{"swiftCode": "TESTPLPWKRK", "countryISO2": "PL"},
+			}},
+			map[string]any{"swiftCode": "OTHRPLPWXXX", "countryName": "POLAND", "branches": []any{}},
+		},
+	}}, response["data"])
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "GetBranchesByHqSwiftCode", mock.Anything, mock.Anything)
+}
+
+// Unit test for a single SWIFT code
+func TestBankQuery(t *testing.T) {
+	mockRepo := new(MockSwiftCodeRepository)
+
+	mockRepo.On("GetBySwiftCode", mock.Anything, "TESTUS33XXX").Return(&model.SwiftEntity{
+		SwiftCode: "TESTUS33XXX", BankName: "Test Bank", Address: "123 Main St", CountryISO2: "US", CountryName: "United States", IsHeadquarter: true,
+	}, nil)
+	mockRepo.On("GetBranchesByHqSwiftCode", mock.Anything, "TESTUS33").Return([]*model.SwiftEntity{
+		{SwiftCode: "TESTUS33ABC", BankName: "Test Bank", Address: "456 Branch St", CountryISO2: "US"},
+	}, nil)
+	mockRepo.On("GetBySwiftCode", mock.Anything, "MISSUS33XXX").Return(nil, nil)
+	mockRepo.On("GetByCountry", mock.Anything, "DE").Return(nil, errors.New("db down"))
+
+	status, response := query(t, mockRepo, `{"query":"{ bank(swiftCode: \"TESTUS33XXX\") { bankName branches { swiftCode } } missing: bank(swiftCode: \"MISSUS33XXX\") { bankName } }"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, map[string]any{
+		"bank":    map[string]any{"bankName": "Test Bank", "branches": []any{map[string]any{"swiftCode": "TESTUS33ABC"}}},
+		"missing": nil,
+	}, response["data"])
+
+	// Repository failures are reported without their details
+	_, response = query(t, mockRepo, `{"query":"{ country(iso2: \"DE\") { name } }"}`)
+	errs, _ := response["errors"].([]any)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, "internal error", errs[0].(map[string]any)["message"])
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+// Unit test for malformed requests
+func TestInvalidRequests(t *testing.T) {
+	mockRepo := new(MockSwiftCodeRepository)
+
+	status, _ := query(t, mockRepo, `{"query":`)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = query(t, mockRepo, `{}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	// Unknown fields are reported as query errors
+	status, response := query(t, mockRepo, `{"query":"{ bank(swiftCode: \"TESTUS33XXX\") { iban } }"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.NotEmpty(t, response["errors"])
+
+	rr := httptest.NewRecorder()
+	NewHandler(service.NewSwiftCodeService(mockRepo)).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/graphql", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+
+	mockRepo.AssertNotCalled(t, "GetBySwiftCode", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,147 @@
+package graphqlapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/dodskygge/go_swift/internal/service"
+)
+
+// Error returned to clients for unexpected failures, whose details are only logged
+var errInternal = errors.New("internal error")
+
+// Root resolver of the schema
+type resolver struct {
+	svc *service.SwiftCodeService
+}
+
+func (r *resolver) Bank(ctx context.Context, args struct{ SwiftCode string }) (*bankResolver, error) {
+	result, err := r.svc.GetSwiftCodeDetails(ctx, args.SwiftCode)
+	if err != nil {
+		return nil, internalError(ctx, err)
+	}
+	if result == nil {
+		return nil, nil
+	}
+	// Details of a headquarters already include its branches
+	return &bankResolver{code: *result, branches: result.Branches}, nil
+}
+
+func (r *resolver) Country(ctx context.Context, args struct{ Iso2 string }) (*countryResolver, error) {
+	result, err := r.svc.GetSwiftCodesByCountry(ctx, args.Iso2)
+	if err != nil {
+		return nil, internalError(ctx, err)
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return &countryResolver{svc: r.svc, country: result}, nil
+}
+
+type countryResolver struct {
+	svc     *service.SwiftCodeService
+	country *model.SwiftCodesByCountryResponse
+}
+
+func (r *countryResolver) Iso2() string { return r.country.CountryISO2 }
+func (r *countryResolver) Name() string { return r.country.CountryName }
+
+func (r *countryResolver) Banks(args struct{ IsHeadquarter *bool }) []*bankResolver {
+	var hqCodes []string
+	var banks []*bankResolver
+	loader := &branchLoader{svc: r.svc}
+	for _, code := range r.country.SwiftCodes {
+		if args.IsHeadquarter != nil && code.IsHeadquarter != *args.IsHeadquarter {
+			continue
+		}
+		if code.IsHeadquarter {
+			hqCodes = append(hqCodes, code.SwiftCode)
+		}
+		banks = append(banks, &bankResolver{
+			code: model.SwiftCodeResponse{
+				Address:       code.Address,
+				BankName:      code.BankName,
+				CountryISO2:   code.CountryISO2,
+				CountryName:   r.country.CountryName,
+				IsHeadquarter: code.IsHeadquarter,
+				SwiftCode:     code.SwiftCode,
+			},
+			loader: loader,
+		})
+	}
+	loader.hqCodes = hqCodes
+	return banks
+}
+
+type bankResolver struct {
+	code model.SwiftCodeResponse
+	// Branches known up front, otherwise loaded together with those of the other listed headquarters
+	branches []model.SwiftCodeBranch
+	loader   *branchLoader
+}
+
+func (r *bankResolver) SwiftCode() string   { return r.code.SwiftCode }
+func (r *bankResolver) BankName() string    { return r.code.BankName }
+func (r *bankResolver) Address() string     { return r.code.Address }
+func (r *bankResolver) CountryISO2() string { return r.code.CountryISO2 }
+func (r *bankResolver) CountryName() string { return r.code.CountryName }
+func (r *bankResolver) IsHeadquarter() bool { return r.code.IsHeadquarter }
+
+func (r *bankResolver) Branches(ctx context.Context) ([]*branchResolver, error) {
+	branches := r.branches
+	if r.code.IsHeadquarter && r.loader != nil {
+		var err error
+		if branches, err = r.loader.load(ctx, r.code.SwiftCode); err != nil {
+			return nil, err
+		}
+	}
+
+	resolvers := make([]*branchResolver, len(branches))
+	for i := range branches {
+		resolvers[i] = &branchResolver{branch: branches[i]}
+	}
+	return resolvers, nil
+}
+
+type branchResolver struct {
+	branch model.SwiftCodeBranch
+}
+
+func (r *branchResolver) SwiftCode() string   { return r.branch.SwiftCode }
+func (r *branchResolver) BankName() string    { return r.branch.BankName }
+func (r *branchResolver) Address() string     { return r.branch.Address }
+func (r *branchResolver) CountryISO2() string { return r.branch.CountryISO2 }
+func (r *branchResolver) IsHeadquarter() bool { return r.branch.IsHeadquarter }
+
+// Loads the branches of every headquarters in a list with one service call, the first time any of them
+// resolves its branches, so listing a country with branches does not query the repository per headquarters
+type branchLoader struct {
+	svc     *service.SwiftCodeService
+	hqCodes []string
+
+	once     sync.Once
+	branches map[string][]model.SwiftCodeBranch
+	err      error
+}
+
+func (l *branchLoader) load(ctx context.Context, hqCode string) ([]model.SwiftCodeBranch, error) {
+	l.once.Do(func() {
+		l.branches, l.err = l.svc.GetBranchesByHeadquarters(ctx, l.hqCodes)
+		if l.err != nil {
+			l.err = internalError(ctx, l.err)
+		}
+	})
+	if l.err != nil {
+		return nil, l.err
+	}
+	return l.branches[hqCode], nil
+}
+
+// Logs an unexpected failure and returns the error shown to the client instead
+func internalError(ctx context.Context, err error) error {
+	slog.ErrorContext(ctx, "GraphQL resolver failed", "error", err)
+	return errInternal
+}
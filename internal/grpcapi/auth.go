@@ -0,0 +1,94 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	swiftv1 "github.com/dodskygge/go_swift/api/swift/v1"
+	"github.com/dodskygge/go_swift/internal/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Policy lists the scope required per full method name, with the same scopes as the REST routes.
+// Methods without a rule, such as server reflection, require the admin scope.
+var Policy = auth.Policy{
+	{Method: "*", Route: healthpb.Health_Check_FullMethodName, Scope: auth.Public},
+	{Method: "*", Route: healthpb.Health_Watch_FullMethodName, Scope: auth.Public},
+	{Method: "*", Route: swiftv1.SwiftCodeService_GetSwiftCode_FullMethodName, Scope: auth.ScopeRead},
+	{Method: "*", Route: swiftv1.SwiftCodeService_ListByCountry_FullMethodName, Scope: auth.ScopeRead},
+	{Method: "*", Route: swiftv1.SwiftCodeService_Search_FullMethodName, Scope: auth.ScopeRead},
+	{Method: "*", Route: swiftv1.SwiftCodeService_Export_FullMethodName, Scope: auth.ScopeRead},
+	{Method: "*", Route: swiftv1.SwiftCodeService_Create_FullMethodName, Scope: auth.ScopeWrite},
+	{Method: "*", Route: swiftv1.SwiftCodeService_Delete_FullMethodName, Scope: auth.ScopeWrite},
+}
+
+// Auth returns an interceptor authenticating calls and enforcing the policy.
+func Auth(policy auth.Policy, authenticators ...auth.Authenticator) Interceptor {
+	return func(ctx context.Context, method string, next func(context.Context) error) error {
+		ctx, err := authorize(ctx, method, policy, authenticators)
+		if err != nil {
+			return err
+		}
+		return next(ctx)
+	}
+}
+
+// Authenticates a call with the HTTP authenticators and checks the scope required for the method,
+// returning a context carrying the principal
+func authorize(ctx context.Context, method string, policy auth.Policy, authenticators []auth.Authenticator) (context.Context, error) {
+	principal, err := auth.Authenticate(callRequest(ctx, method), authenticators...)
+	if err != nil && !errors.Is(err, auth.ErrInvalidCredentials) {
+		slog.ErrorContext(ctx, "authentication failed", "error", err)
+		return nil, status.Error(codes.Internal, "authentication failed")
+	}
+
+	scope := policy.Required(http.MethodPost, method)
+	if scope != auth.Public {
+		if principal == nil {
+			return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+		}
+		if !principal.HasScope(scope) {
+			slog.WarnContext(ctx, "insufficient scope", "principal", principal.ID, "required_scope", scope)
+			return nil, status.Error(codes.PermissionDenied, "permission denied")
+		}
+	}
+
+	if principal != nil {
+		ctx = auth.WithPrincipal(ctx, principal)
+	}
+	return ctx, nil
+}
+
+// Builds the HTTP request the authenticators inspect from the metadata and TLS state of a call
+func callRequest(ctx context.Context, method string) *http.Request {
+	r := (&http.Request{
+		Method: http.MethodPost,
+		URL:    &url.URL{Path: method},
+		Header: make(http.Header),
+	}).WithContext(ctx)
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for key, values := range md {
+		for _, value := range values {
+			r.Header.Add(key, value)
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		if p.Addr != nil {
+			r.RemoteAddr = p.Addr.String()
+		}
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			r.TLS = &info.State
+		}
+	}
+	return r
+}
@@ -0,0 +1,200 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/dodskygge/go_swift/internal/logging"
+	"github.com/dodskygge/go_swift/internal/metrics"
+	"github.com/dodskygge/go_swift/internal/middleware"
+	"github.com/dodskygge/go_swift/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Interceptor wraps the handling of a unary or streaming call given its context and full method name,
+// the gRPC counterpart of an HTTP middleware. Unary and Stream adapt it to the server interceptor types.
+type Interceptor func(ctx context.Context, method string, next func(context.Context) error) error
+
+// Unary adapts an interceptor to unary calls.
+func Unary(interceptor Interceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var resp any
+		err := interceptor(ctx, info.FullMethod, func(ctx context.Context) (err error) {
+			resp, err = handler(ctx, req)
+			return err
+		})
+		return resp, err
+	}
+}
+
+// Stream adapts an interceptor to streaming calls.
+func Stream(interceptor Interceptor) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return interceptor(stream.Context(), info.FullMethod, func(ctx context.Context) error {
+			return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+		})
+	}
+}
+
+// RequestIDMetadata is the metadata key used to receive and return request IDs, as X-Request-ID over HTTP.
+const RequestIDMetadata = "x-request-id"
+
+// RequestID reuses a valid incoming x-request-id or generates a new one, stores it in the call context
+// and returns it in the response headers.
+func RequestID(ctx context.Context, method string, next func(context.Context) error) error {
+	var id string
+	if values := metadata.ValueFromIncomingContext(ctx, RequestIDMetadata); len(values) > 0 {
+		id = values[0]
+	}
+	id = middleware.RequestIDOrNew(id)
+
+	grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadata, id))
+	return next(logging.WithRequestID(ctx, id))
+}
+
+// Tracing starts a server span for every call, continuing the trace from incoming traceparent metadata.
+func Tracing(ctx context.Context, method string, next func(context.Context) error) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+
+	ctx, span := tracing.Tracer().Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.method", method),
+		),
+	)
+	defer span.End()
+
+	err := next(ctx)
+
+	code := status.Code(err)
+	span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(code)))
+	if serverError(code) {
+		span.SetStatus(otelcodes.Error, code.String())
+	}
+	return err
+}
+
+// Metrics records call counts and latency histograms per method and status code.
+func Metrics(ctx context.Context, method string, next func(context.Context) error) error {
+	start := time.Now()
+	err := next(ctx)
+
+	code := status.Code(err).String()
+	metrics.GRPCRequests.WithLabelValues(method, code).Inc()
+	metrics.GRPCDuration.WithLabelValues(method, code).Observe(time.Since(start).Seconds())
+	return err
+}
+
+// AccessLog logs one record per call with its method, status code, latency and peer address.
+func AccessLog(logger *slog.Logger) Interceptor {
+	return func(ctx context.Context, method string, next func(context.Context) error) error {
+		start := time.Now()
+		err := next(ctx)
+
+		code := status.Code(err)
+		level := slog.LevelInfo
+		if serverError(code) {
+			level = slog.LevelError
+		}
+
+		var remoteAddr string
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			remoteAddr = p.Addr.String()
+		}
+
+		logger.LogAttrs(ctx, level, "call completed",
+			slog.String("method", method),
+			slog.String("code", code.String()),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("remote_addr", remoteAddr),
+		)
+		return err
+	}
+}
+
+// RateLimit rejects calls exceeding the limit of their full method name with RESOURCE_EXHAUSTED and a
+// retry-after header in seconds. Authenticated callers are limited per principal, anonymous ones per
+// client IP, so it runs after authentication.
+func RateLimit(limiter *middleware.RateLimiter) Interceptor {
+	return func(ctx context.Context, method string, next func(context.Context) error) error {
+		allowed, retryAfter := limiter.Allow(callRequest(ctx, method), method)
+		if !allowed {
+			grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))))
+			return status.Error(codes.ResourceExhausted, "too many requests")
+		}
+		return next(ctx)
+	}
+}
+
+// AuthFailures limits failed authentication attempts per client IP, like the HTTP middleware of the rate
+// limiter, so it runs before authentication. Calls failing with UNAUTHENTICATED or PERMISSION_DENIED take an
+// attempt, and clients out of attempts get RESOURCE_EXHAUSTED.
+func AuthFailures(limiter *middleware.RateLimiter) Interceptor {
+	return func(ctx context.Context, method string, next func(context.Context) error) error {
+		r := callRequest(ctx, method)
+		if allowed, retryAfter := limiter.AllowAuthAttempt(r); !allowed {
+			grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))))
+			return status.Error(codes.ResourceExhausted, "too many requests")
+		}
+
+		err := next(ctx)
+		if code := status.Code(err); code == codes.Unauthenticated || code == codes.PermissionDenied {
+			limiter.RecordAuthFailure(r)
+		}
+		return err
+	}
+}
+
+// Reports whether a status code is a failure of the server rather than of the call, like a 5xx status
+func serverError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.Unimplemented, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// Carries trace context in call metadata for the OpenTelemetry propagator
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Server stream whose context is replaced, e.g. to carry the principal or request ID
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,257 @@
+// Package grpcapi serves the SWIFT code API over gRPC, backed by the same service as the REST handlers.
+package grpcapi
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+
+	swiftv1 "github.com/dodskygge/go_swift/api/swift/v1"
+	"github.com/dodskygge/go_swift/internal/auth"
+	"github.com/dodskygge/go_swift/internal/middleware"
+	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/dodskygge/go_swift/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements swift.v1.SwiftCodeService.
+type Server struct {
+	swiftv1.UnimplementedSwiftCodeServiceServer
+	Service *service.SwiftCodeService
+}
+
+// Options configures the interceptors of the server returned by NewServer.
+type Options struct {
+	// Authenticators, when set, require calls to carry credentials granting the scope required by Policy.
+	Authenticators []auth.Authenticator
+	// RateLimiter, when set, limits calls per principal or client IP and full method name.
+	RateLimiter *middleware.RateLimiter
+	// Logger, when set, writes one access log record per call.
+	Logger *slog.Logger
+}
+
+// NewServer returns a gRPC server exposing the SWIFT code API, the standard health service and server
+// reflection. Like the REST API, calls are traced, given a request ID, logged, measured, authenticated
+// and rate limited as configured by options. The returned health server reports SERVING until it is
+// shut down.
+func NewServer(svc *service.SwiftCodeService, options Options, opts ...grpc.ServerOption) (*grpc.Server, *health.Server) {
+	interceptors := []Interceptor{Tracing, RequestID}
+	if options.Logger != nil {
+		interceptors = append(interceptors, AccessLog(options.Logger))
+	}
+	interceptors = append(interceptors, Metrics)
+	if len(options.Authenticators) > 0 {
+		if options.RateLimiter != nil {
+			interceptors = append(interceptors, AuthFailures(options.RateLimiter))
+		}
+		interceptors = append(interceptors, Auth(Policy, options.Authenticators...))
+	}
+	if options.RateLimiter != nil {
+		interceptors = append(interceptors, RateLimit(options.RateLimiter))
+	}
+	for _, interceptor := range interceptors {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(Unary(interceptor)),
+			grpc.ChainStreamInterceptor(Stream(interceptor)),
+		)
+	}
+
+	server := grpc.NewServer(opts...)
+	swiftv1.RegisterSwiftCodeServiceServer(server, &Server{Service: svc})
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(swiftv1.SwiftCodeService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+	return server, healthServer
+}
+
+// GetSwiftCode returns a SWIFT code with its branches.
+func (s *Server) GetSwiftCode(ctx context.Context, req *swiftv1.GetSwiftCodeRequest) (*swiftv1.GetSwiftCodeResponse, error) {
+	result, err := s.Service.GetSwiftCodeDetails(ctx, req.GetSwiftCode())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	if result == nil {
+		return nil, status.Error(codes.NotFound, "SWIFT code not found")
+	}
+
+	code := toSwiftCode(*result)
+	for _, branch := range result.Branches {
+		code.Branches = append(code.Branches, &swiftv1.SwiftCode{
+			SwiftCode:     branch.SwiftCode,
+			BankName:      branch.BankName,
+			Address:       branch.Address,
+			CountryIso2:   branch.CountryISO2,
+			IsHeadquarter: branch.IsHeadquarter,
+		})
+	}
+	return &swiftv1.GetSwiftCodeResponse{SwiftCode: code}, nil
+}
+
+// ListByCountry returns the SWIFT codes of a country.
+func (s *Server) ListByCountry(ctx context.Context, req *swiftv1.ListByCountryRequest) (*swiftv1.ListByCountryResponse, error) {
+	if req.GetCountryIso2() == "" {
+		return nil, status.Error(codes.InvalidArgument, "country_iso2 is required")
+	}
+
+	result, err := s.Service.GetSwiftCodesByCountry(ctx, req.GetCountryIso2())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	response := &swiftv1.ListByCountryResponse{CountryIso2: req.GetCountryIso2()}
+	if result == nil {
+		return response, nil
+	}
+	response.CountryIso2 = result.CountryISO2
+	response.CountryName = result.CountryName
+	for _, code := range result.SwiftCodes {
+		response.SwiftCodes = append(response.SwiftCodes, &swiftv1.SwiftCode{
+			SwiftCode:     code.SwiftCode,
+			BankName:      code.BankName,
+			Address:       code.Address,
+			CountryIso2:   code.CountryISO2,
+			CountryName:   result.CountryName,
+			IsHeadquarter: code.IsHeadquarter,
+		})
+	}
+	return response, nil
+}
+
+// Search returns one page of the SWIFT codes matching the query.
+func (s *Server) Search(ctx context.Context, req *swiftv1.SearchRequest) (*swiftv1.SearchResponse, error) {
+	if req.GetPageSize() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "page_size must not be negative")
+	}
+	after, err := base64.RawURLEncoding.DecodeString(req.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+	}
+
+	results, next, err := s.Service.SearchSwiftCodes(ctx, model.SwiftCodeSearch{
+		Query:  req.GetQuery(),
+		Filter: model.SwiftCodeFilter{CountryISO2: req.GetCountryIso2(), IsHeadquarter: req.IsHeadquarter},
+		After:  string(after),
+		Limit:  int(req.GetPageSize()),
+	})
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	response := &swiftv1.SearchResponse{NextPageToken: base64.RawURLEncoding.EncodeToString([]byte(next))}
+	for _, result := range results {
+		response.SwiftCodes = append(response.SwiftCodes, toSwiftCode(result))
+	}
+	return response, nil
+}
+
+// Create stores a new SWIFT code and returns it.
+func (s *Server) Create(ctx context.Context, req *swiftv1.CreateRequest) (*swiftv1.CreateResponse, error) {
+	err := s.Service.CreateSwiftCode(ctx, model.CreateSwiftCodeRequest{
+		Address:       req.GetAddress(),
+		BankName:      req.GetBankName(),
+		CountryISO2:   req.GetCountryIso2(),
+		CountryName:   req.GetCountryName(),
+		IsHeadquarter: req.GetIsHeadquarter(),
+		SwiftCode:     req.GetSwiftCode(),
+	})
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	result, err := s.Service.GetSwiftCodeDetails(ctx, req.GetSwiftCode())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	if result == nil {
+		return nil, status.Error(codes.Internal, "created SWIFT code not found")
+	}
+	return &swiftv1.CreateResponse{SwiftCode: toSwiftCode(*result)}, nil
+}
+
+// Delete removes a SWIFT code, provided it is still at the expected version. Like If-Match over REST the
+// version is required, 0 deleting any version.
+func (s *Server) Delete(ctx context.Context, req *swiftv1.DeleteRequest) (*swiftv1.DeleteResponse, error) {
+	if req.ExpectedVersion == nil {
+		return nil, status.Error(codes.FailedPrecondition, "expected_version is required, 0 deletes any version")
+	}
+	if req.GetExpectedVersion() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "expected_version must not be negative")
+	}
+	if err := s.Service.DeleteSwiftCode(ctx, req.GetSwiftCode(), req.GetExpectedVersion()); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &swiftv1.DeleteResponse{}, nil
+}
+
+// Export streams the matching SWIFT codes ordered by code.
+func (s *Server) Export(req *swiftv1.ExportRequest, stream grpc.ServerStreamingServer[swiftv1.ExportResponse]) error {
+	ctx := stream.Context()
+	filter := model.SwiftCodeFilter{CountryISO2: req.GetCountryIso2(), IsHeadquarter: req.IsHeadquarter}
+
+	err := s.Service.ExportSwiftCodes(ctx, filter, func(record model.CreateSwiftCodeRequest) error {
+		return stream.Send(&swiftv1.ExportResponse{SwiftCode: &swiftv1.SwiftCode{
+			SwiftCode:     record.SwiftCode,
+			BankName:      record.BankName,
+			Address:       record.Address,
+			CountryIso2:   record.CountryISO2,
+			CountryName:   record.CountryName,
+			IsHeadquarter: record.IsHeadquarter,
+		}})
+	})
+	if err != nil {
+		return toStatus(ctx, err)
+	}
+	return nil
+}
+
+// Converts a SWIFT code response, without its branches
+func toSwiftCode(result model.SwiftCodeResponse) *swiftv1.SwiftCode {
+	code := &swiftv1.SwiftCode{
+		SwiftCode:     result.SwiftCode,
+		BankName:      result.BankName,
+		Address:       result.Address,
+		CountryIso2:   result.CountryISO2,
+		CountryName:   result.CountryName,
+		IsHeadquarter: result.IsHeadquarter,
+		Version:       result.Version,
+	}
+	if !result.LastModified.IsZero() {
+		code.UpdateTime = timestamppb.New(result.LastModified)
+	}
+	return code
+}
+
+// Maps service errors to gRPC status errors, hiding the details of unexpected failures
+func toStatus(ctx context.Context, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		return status.Error(codes.NotFound, "SWIFT code not found")
+	case errors.Is(err, model.ErrAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, model.ErrVersionMismatch):
+		return status.Error(codes.Aborted, "SWIFT code has been modified")
+	case errors.Is(err, model.ErrInvalidInput):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	slog.ErrorContext(ctx, "gRPC call failed", "error", err)
+	return status.Error(codes.Internal, "internal error")
+}
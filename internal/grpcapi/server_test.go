@@ -0,0 +1,397 @@
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"testing"
+
+	swiftv1 "github.com/dodskygge/go_swift/api/swift/v1"
+	"github.com/dodskygge/go_swift/internal/auth"
+	"github.com/dodskygge/go_swift/internal/logging"
+	"github.com/dodskygge/go_swift/internal/metrics"
+	"github.com/dodskygge/go_swift/internal/middleware"
+	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/dodskygge/go_swift/internal/service"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// Mock repository for testing
+type MockSwiftCodeRepository struct {
+	mock.Mock
+}
+
+func (m *MockSwiftCodeRepository) GetBySwiftCode(ctx context.Context, code string) (*model.SwiftEntity, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SwiftEntity), args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) GetBranchesByHqSwiftCode(ctx context.Context, hqCode string) ([]*model.SwiftEntity, error) {
+	args := m.Called(ctx, hqCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SwiftEntity), args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) GetBranchesByHqSwiftCodes(ctx context.Context, hqCodes []string) ([]*model.SwiftEntity, error) {
+	args := m.Called(ctx, hqCodes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SwiftEntity), args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) GetByCountry(ctx context.Context, countryISO2 string) ([]*model.SwiftEntity, error) {
+	args := m.Called(ctx, countryISO2)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SwiftEntity), args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) GetBySwiftCodes(ctx context.Context, swiftCodes []string) ([]*model.SwiftEntity, error) {
+	args := m.Called(ctx, swiftCodes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SwiftEntity), args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) Search(ctx context.Context, search model.SwiftCodeSearch) ([]*model.SwiftEntity, error) {
+	args := m.Called(ctx, search)
+	entities, _ := args.Get(0).([]*model.SwiftEntity)
+	return entities, args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) ForEach(ctx context.Context, filter model.SwiftCodeFilter, fn func(*model.SwiftEntity) error) error {
+	args := m.Called(ctx, filter, fn)
+	if entities, ok := args.Get(0).([]*model.SwiftEntity); ok {
+		for _, entity := range entities {
+			if err := fn(entity); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) Create(ctx context.Context, swift *model.SwiftEntity) error {
+	args := m.Called(ctx, swift)
+	return args.Error(0)
+}
+
+func (m *MockSwiftCodeRepository) BulkWrite(ctx context.Context, entities []*model.SwiftEntity, upsert, atomic bool) ([]model.BulkItemResult, bool, error) {
+	args := m.Called(ctx, entities, upsert, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).([]model.BulkItemResult), args.Bool(1), args.Error(2)
+}
+
+func (m *MockSwiftCodeRepository) Update(ctx context.Context, swift *model.SwiftEntity, expectedVersion int64) error {
+	args := m.Called(ctx, swift, expectedVersion)
+	return args.Error(0)
+}
+
+func (m *MockSwiftCodeRepository) Delete(ctx context.Context, swiftCode string, expectedVersion int64) error {
+	args := m.Called(ctx, swiftCode, expectedVersion)
+	return args.Error(0)
+}
+
+func (m *MockSwiftCodeRepository) ListCountries(ctx context.Context) ([]*model.Country, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Country), args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) Stats(ctx context.Context, countryISO2 string) (*model.SwiftCodeStats, error) {
+	args := m.Called(ctx, countryISO2)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SwiftCodeStats), args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) GetAuditLog(ctx context.Context, query model.AuditQuery) ([]model.AuditEntry, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AuditEntry), args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) GetByInstitution(ctx context.Context, bic4 string) ([]*model.SwiftEntity, error) {
+	args := m.Called(ctx, bic4)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SwiftEntity), args.Error(1)
+}
+
+// Reference countries returned by the mock repository
+var testCountries = []*model.Country{
+	{ISO2: "PL", ISO3: "POL", Numeric: "616", Name: "Poland"},
+	{ISO2: "US", ISO3: "USA", Numeric: "840", Name: "United States"},
+}
+
+// Authenticator accepting fixed API keys
+type staticAuthenticator map[string][]string
+
+func (a staticAuthenticator) Authenticate(r *http.Request) (*auth.Principal, error) {
+	key := r.Header.Get(auth.APIKeyHeader)
+	if key == "" {
+		return nil, nil
+	}
+	scopes, ok := a[key]
+	if !ok {
+		return nil, auth.ErrInvalidCredentials
+	}
+	return &auth.Principal{ID: "apikey:" + key, Scopes: scopes}, nil
+}
+
+func (a staticAuthenticator) Challenge() string {
+	return ""
+}
+
+// Starts a server backed by repo on an in-memory listener and returns a connection to it
+func dial(t *testing.T, repo service.SwiftCodeRepository, authenticators ...auth.Authenticator) *grpc.ClientConn {
+	return dialWithOptions(t, repo, Options{Authenticators: authenticators})
+}
+
+// Starts a server backed by repo with options on an in-memory listener and returns a connection to it
+func dialWithOptions(t *testing.T, repo service.SwiftCodeRepository, options Options) *grpc.ClientConn {
+	listener := bufconn.Listen(1 << 20)
+	server, _ := NewServer(service.NewSwiftCodeService(repo), options)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// Unit test for GetSwiftCode
+func TestGetSwiftCode(t *testing.T) {
+	mockRepo := new(MockSwiftCodeRepository)
+	client := swiftv1.NewSwiftCodeServiceClient(dial(t, mockRepo))
+
+	mockRepo.On("GetBySwiftCode", mock.Anything, "TESTUS33XXX").Return(&model.SwiftEntity{
+		SwiftCode: "TESTUS33XXX", BankName: "Test Bank", Address: "123 Main St", CountryISO2: "us", CountryName: "United States", IsHeadquarter: true, Version: 3,
+	}, nil)
+	mockRepo.On("GetBranchesByHqSwiftCode", mock.Anything, "TESTUS33").Return([]*model.SwiftEntity{
+		{SwiftCode: "TESTUS33ABC", BankName: "Test Bank", Address: "456 Branch St", CountryISO2: "US"},
+	}, nil)
+	mockRepo.On("GetBySwiftCode", mock.Anything, "MISSUS33XXX").Return(nil, nil)
+
+	response, err := client.GetSwiftCode(context.Background(), &swiftv1.GetSwiftCodeRequest{SwiftCode: "TESTUS33XXX"})
+	assert.NoError(t, err)
+	assert.Equal(t, "TESTUS33XXX", response.GetSwiftCode().GetSwiftCode())
+	assert.Equal(t, "UNITED STATES", response.GetSwiftCode().GetCountryName())
+	assert.Equal(t, int64(3), response.GetSwiftCode().GetVersion())
+	assert.Len(t, response.GetSwiftCode().GetBranches(), 1)
+
+	_, err = client.GetSwiftCode(context.Background(), &swiftv1.GetSwiftCodeRequest{SwiftCode: "MISSUS33XXX"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	mockRepo.AssertExpectations(t)
+}
+
+// Unit test for Search paging
+func TestSearch(t *testing.T) {
+	mockRepo := new(MockSwiftCodeRepository)
+	client := swiftv1.NewSwiftCodeServiceClient(dial(t, mockRepo))
+
+	hq := true
+	entities := []*model.SwiftEntity{
+		{SwiftCode: "TESTPLPWXXX", BankName: "Test Bank", CountryISO2: "PL", IsHeadquarter: true},
+		{SwiftCode: "TESTUS33XXX", BankName: "Test Bank", CountryISO2: "US", IsHeadquarter: true},
+	}
+	mockRepo.On("Search", mock.Anything, model.SwiftCodeSearch{Query: "test", Filter: model.SwiftCodeFilter{IsHeadquarter: &hq}, Limit: 2}).Return(entities, nil)
+	mockRepo.On("Search", mock.Anything, model.SwiftCodeSearch{Query: "test", Filter: model.SwiftCodeFilter{IsHeadquarter: &hq}, After: "TESTPLPWXXX", Limit: 2}).Return(entities[1:], nil)
+
+	response, err := client.Search(context.Background(), &swiftv1.SearchRequest{Query: "test", IsHeadquarter: &hq, PageSize: 1})
+	assert.NoError(t, err)
+	assert.Len(t, response.GetSwiftCodes(), 1)
+	assert.NotEmpty(t, response.GetNextPageToken())
+
+	response, err = client.Search(context.Background(), &swiftv1.SearchRequest{Query: "test", IsHeadquarter: &hq, PageSize: 1, PageToken: response.GetNextPageToken()})
+	assert.NoError(t, err)
+	assert.Equal(t, "TESTUS33XXX", response.GetSwiftCodes()[0].GetSwiftCode())
+	assert.Empty(t, response.GetNextPageToken())
+
+	_, err = client.Search(context.Background(), &swiftv1.SearchRequest{PageToken: "not base64!"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	mockRepo.AssertExpectations(t)
+}
+
+// Unit test for Create and Delete error codes
+func TestCreateAndDelete(t *testing.T) {
+	mockRepo := new(MockSwiftCodeRepository)
+	client := swiftv1.NewSwiftCodeServiceClient(dial(t, mockRepo))
+
+	mockRepo.On("ListCountries", mock.Anything).Return(testCountries, nil)
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("GetBySwiftCode", mock.Anything, "TESTUS33XXX").Return(&model.SwiftEntity{SwiftCode: "TESTUS33XXX", IsHeadquarter: true, Version: 1}, nil)
+	mockRepo.On("GetBranchesByHqSwiftCode", mock.Anything, "TESTUS33").Return(nil, nil)
+	mockRepo.On("Delete", mock.Anything, "TESTUS33XXX", int64(2)).Return(model.ErrVersionMismatch)
+	mockRepo.On("Delete", mock.Anything, "TESTUS33XXX", int64(1)).Return(nil)
+
+	created, err := client.Create(context.Background(), &swiftv1.CreateRequest{
+		SwiftCode: "TESTUS33XXX", BankName: "Test Bank", Address: "123 Main St", CountryIso2: "US", CountryName: "United States", IsHeadquarter: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), created.GetSwiftCode().GetVersion())
+
+	_, err = client.Create(context.Background(), &swiftv1.CreateRequest{SwiftCode: "SHORT"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.Delete(context.Background(), &swiftv1.DeleteRequest{SwiftCode: "TESTUS33XXX"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = client.Delete(context.Background(), &swiftv1.DeleteRequest{SwiftCode: "TESTUS33XXX", ExpectedVersion: proto.Int64(2)})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	_, err = client.Delete(context.Background(), &swiftv1.DeleteRequest{SwiftCode: "TESTUS33XXX", ExpectedVersion: proto.Int64(1)})
+	assert.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// Unit test for Export streaming
+func TestExport(t *testing.T) {
+	mockRepo := new(MockSwiftCodeRepository)
+	client := swiftv1.NewSwiftCodeServiceClient(dial(t, mockRepo))
+
+	mockRepo.On("ForEach", mock.Anything, model.SwiftCodeFilter{CountryISO2: "US"}, mock.Anything).Return([]*model.SwiftEntity{
+		{SwiftCode: "TESTUS33ABC", CountryISO2: "US", CountryName: "United States"},
+		{SwiftCode: "TESTUS33XXX", CountryISO2: "US", CountryName: "United States", IsHeadquarter: true},
+	}, nil)
+	mockRepo.On("ForEach", mock.Anything, model.SwiftCodeFilter{CountryISO2: "PL"}, mock.Anything).Return(nil, errors.New("db down"))
+
+	stream, err := client.Export(context.Background(), &swiftv1.ExportRequest{CountryIso2: "us"})
+	assert.NoError(t, err)
+	var exported []string
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			break
+		}
+		exported = append(exported, response.GetSwiftCode().GetSwiftCode())
+		assert.Equal(t, "UNITED STATES", response.GetSwiftCode().GetCountryName())
+	}
+	assert.Equal(t, []string{"TESTUS33ABC", "TESTUS33XXX"}, exported)
+
+	stream, err = client.Export(context.Background(), &swiftv1.ExportRequest{CountryIso2: "PL"})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	mockRepo.AssertExpectations(t)
+}
+
+// Unit test for authentication and the health service
+func TestAuthAndHealth(t *testing.T) {
+	mockRepo := new(MockSwiftCodeRepository)
+	conn := dial(t, mockRepo, staticAuthenticator{"reader": {auth.ScopeRead}})
+	client := swiftv1.NewSwiftCodeServiceClient(conn)
+
+	// Health checks are public
+	health, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{Service: "swift.v1.SwiftCodeService"})
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, health.GetStatus())
+
+	_, err = client.GetSwiftCode(context.Background(), &swiftv1.GetSwiftCodeRequest{SwiftCode: "TESTUS33XXX"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "unknown")
+	_, err = client.GetSwiftCode(ctx, &swiftv1.GetSwiftCodeRequest{SwiftCode: "TESTUS33XXX"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "reader")
+	_, err = client.Delete(ctx, &swiftv1.DeleteRequest{SwiftCode: "TESTUS33XXX"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	mockRepo.On("GetBySwiftCode", mock.Anything, "TESTUS33XXX").Return(nil, nil)
+	_, err = client.GetSwiftCode(ctx, &swiftv1.GetSwiftCodeRequest{SwiftCode: "TESTUS33XXX"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// Streams are authenticated as well
+	stream, err := client.Export(context.Background(), &swiftv1.ExportRequest{})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+// Unit test for the request ID, rate limiting, access log and metrics interceptors
+func TestInterceptors(t *testing.T) {
+	mockRepo := new(MockSwiftCodeRepository)
+	var logs bytes.Buffer
+	conn := dialWithOptions(t, mockRepo, Options{
+		RateLimiter: middleware.NewRateLimiter(middleware.RateLimitOptions{
+			Routes: map[string]middleware.Limit{swiftv1.SwiftCodeService_Delete_FullMethodName: {RPS: 1, Burst: 1}},
+		}),
+		Logger: slog.New(slog.NewTextHandler(&logs, nil)),
+	})
+	client := swiftv1.NewSwiftCodeServiceClient(conn)
+
+	// The incoming request ID reaches the service and is returned
+	var requestID string
+	mockRepo.On("Delete", mock.Anything, "TESTUS33XXX", int64(1)).Run(func(args mock.Arguments) {
+		requestID = logging.RequestIDFromContext(args.Get(0).(context.Context))
+	}).Return(nil)
+	deleted := testutil.ToFloat64(metrics.GRPCRequests.WithLabelValues(swiftv1.SwiftCodeService_Delete_FullMethodName, "OK"))
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "req-1")
+	_, err := client.Delete(ctx, &swiftv1.DeleteRequest{SwiftCode: "TESTUS33XXX", ExpectedVersion: proto.Int64(1)}, grpc.Header(&header))
+	assert.NoError(t, err)
+	assert.Equal(t, "req-1", requestID)
+	assert.Equal(t, []string{"req-1"}, header.Get("x-request-id"))
+	assert.Equal(t, deleted+1, testutil.ToFloat64(metrics.GRPCRequests.WithLabelValues(swiftv1.SwiftCodeService_Delete_FullMethodName, "OK")))
+	assert.Contains(t, logs.String(), "msg=\"call completed\" method=/swift.v1.SwiftCodeService/Delete code=OK")
+
+	// Calls over the limit are rejected, and still get a request ID
+	header = nil
+	_, err = client.Delete(context.Background(), &swiftv1.DeleteRequest{SwiftCode: "TESTUS33XXX", ExpectedVersion: proto.Int64(1)}, grpc.Header(&header))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, []string{"1"}, header.Get("retry-after"))
+	assert.Len(t, header.Get("x-request-id"), 1)
+	assert.NotEmpty(t, header.Get("x-request-id")[0])
+
+	// Other methods are not limited
+	mockRepo.On("GetBySwiftCode", mock.Anything, "TESTUS33XXX").Return(nil, nil)
+	for range 2 {
+		_, err = client.GetSwiftCode(context.Background(), &swiftv1.GetSwiftCodeRequest{SwiftCode: "TESTUS33XXX"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	}
+
+	mockRepo.AssertExpectations(t)
+}
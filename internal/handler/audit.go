@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dodskygge/go_swift/internal/model"
+)
+
+// Handles GET /api/v1/swift-codes/{swift-code}/history?after={id}&limit={n}, listing the changes of a
+// SWIFT code oldest first, including those of a deleted code
+func SwiftCodeHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	prefix, suffix := "/api/v1/swift-codes/", "/history"
+	if !strings.HasPrefix(r.URL.Path, prefix) || !strings.HasSuffix(r.URL.Path, suffix) {
+		http.NotFound(w, r)
+		return
+	}
+	swiftCode := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, prefix), suffix)
+	if swiftCode == "" || strings.Contains(swiftCode, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	var query model.AuditQuery
+	if err := parseAuditPage(r.URL.Query(), &query); err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := SwiftService.GetSwiftCodeHistory(r.Context(), swiftCode, query.After, query.Limit)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidInput) {
+			writeError(w, r, http.StatusBadRequest, err.Error())
+		} else if errors.Is(err, model.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, "SWIFT code history not found")
+		} else {
+			writeError(w, r, http.StatusInternalServerError, "Error fetching SWIFT code history")
+		}
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, result)
+}
+
+// Handles GET /api/v1/admin/audit-log?swiftCode=&actor=&action=&since=&until=&after=&limit=, listing the
+// audit log entries matching every given filter oldest first; since and until are RFC 3339 times
+func AuditLogHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	params := r.URL.Query()
+	query := model.AuditQuery{
+		SwiftCode: params.Get("swiftCode"),
+		Actor:     params.Get("actor"),
+		Action:    params.Get("action"),
+	}
+	if err := parseAuditPage(params, &query); err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	for name, t := range map[string]*time.Time{"since": &query.Since, "until": &query.Until} {
+		if value := params.Get(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				writeError(w, r, http.StatusBadRequest, "Invalid "+name+", expected an RFC 3339 time")
+				return
+			}
+			*t = parsed
+		}
+	}
+
+	result, err := SwiftService.QueryAuditLog(r.Context(), query)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidInput) {
+			writeError(w, r, http.StatusBadRequest, err.Error())
+		} else {
+			writeError(w, r, http.StatusInternalServerError, "Error fetching audit log")
+		}
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, result)
+}
+
+// Reads the after and limit paging parameters of audit log queries
+func parseAuditPage(params url.Values, query *model.AuditQuery) error {
+	if after := params.Get("after"); after != "" {
+		id, err := strconv.ParseInt(after, 10, 64)
+		if err != nil || id < 0 {
+			return errors.New("Invalid after, expected an audit log entry ID")
+		}
+		query.After = id
+	}
+	if limit := params.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return errors.New("Invalid limit, expected a positive number")
+		}
+		query.Limit = n
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/dodskygge/go_swift/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Unit test for SwiftCodeHistoryHandler
+func TestSwiftCodeHistoryHandler(t *testing.T) {
+	mockService := new(MockSwiftCodeService)
+	SwiftService = service.NewSwiftCodeService(mockService)
+
+	changedAt := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+	mockService.On("GetAuditLog", mock.Anything, model.AuditQuery{SwiftCode: "TESTUS33XXX", After: 4, Limit: 2}).Return([]model.AuditEntry{
+		{
+			ID: 5, Time: changedAt, Actor: "apikey:1", Action: model.AuditActionDelete, SwiftCode: "TESTUS33XXX", RequestID: "req-1",
+			Before: &model.AuditSnapshot{Address: "123 Main St", BankName: "Test Bank", CountryISO2: "US", CountryName: "UNITED STATES", IsHeadquarter: true, SwiftCode: "TESTUS33XXX", Version: 2},
+		},
+	}, nil)
+	mockService.On("GetAuditLog", mock.Anything, model.AuditQuery{SwiftCode: "NONEUS33XXX", Limit: service.DefaultSearchLimit + 1}).Return(nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/TESTUS33XXX/history?after=4&limit=1", nil)
+	rec := httptest.NewRecorder()
+	SwiftCodeHistoryHandler(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"entries":[{"id":5,"time":"2025-05-01T10:00:00Z","actor":"apikey:1","action":"delete","swiftCode":"TESTUS33XXX","requestId":"req-1",
+		"before":{"address":"123 Main St","bankName":"Test Bank","countryISO2":"US","countryName":"UNITED STATES","isHeadquarter":true,"swiftCode":"TESTUS33XXX","version":2}}]}`, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/NONEUS33XXX/history", nil)
+	rec = httptest.NewRecorder()
+	SwiftCodeHistoryHandler(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/TESTUS33XXX/history?limit=0", nil)
+	rec = httptest.NewRecorder()
+	SwiftCodeHistoryHandler(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+// Unit test for AuditLogHandler
+func TestAuditLogHandler(t *testing.T) {
+	mockService := new(MockSwiftCodeService)
+	SwiftService = service.NewSwiftCodeService(mockService)
+
+	since := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetAuditLog", mock.Anything, model.AuditQuery{
+		Actor: "apikey:1", Action: model.AuditActionCreate, Since: since, Limit: 2,
+	}).Return([]model.AuditEntry{
+		{ID: 1, Time: since, Actor: "apikey:1", Action: model.AuditActionCreate, SwiftCode: "TESTUS33XXX"},
+		{ID: 3, Time: since, Actor: "apikey:1", Action: model.AuditActionCreate, SwiftCode: "TESTPLPWXXX"},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/audit-log?actor=apikey:1&action=create&since=2025-05-01T00:00:00Z&limit=1", nil)
+	rec := httptest.NewRecorder()
+	AuditLogHandler(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"entries":[{"id":1,"time":"2025-05-01T00:00:00Z","actor":"apikey:1","action":"create","swiftCode":"TESTUS33XXX"}],"next":1}`, rec.Body.String())
+
+	for _, query := range []string{"action=read", "since=yesterday", "after=-1"} {
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/admin/audit-log?"+query, nil)
+		rec = httptest.NewRecorder()
+		AuditLogHandler(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+
+	mockService.AssertExpectations(t)
+}
@@ -2,9 +2,13 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/dodskygge/go_swift/internal/model"
 	"github.com/dodskygge/go_swift/internal/service"
@@ -12,6 +16,39 @@ import (
 
 var SwiftService *service.SwiftCodeService
 
+// Bytes allowed per code of a batch lookup body, with room for quotes, separators and whitespace, per record
+// of a bulk write body, and for the rest of a body
+const (
+	batchLookupBytesPerCode = 64
+	bulkWriteBytesPerRecord = 2 << 10
+	bodyOverheadBytes       = 4 << 10
+)
+
+// MaxBodySizes returns the largest request bodies accepted per route pattern when batches hold at most
+// maxBatchSize codes, for limiting bodies before they are read by middleware.
+func MaxBodySizes(maxBatchSize int) map[string]int64 {
+	return map[string]int64{
+		"/api/v1/swift-codes/batch-lookup": batchLookupBodyLimit(maxBatchSize),
+		"/api/v1/swift-codes/bulk":         bulkWriteBodyLimit(maxBatchSize),
+	}
+}
+
+// Largest body of a bulk write of maxBatchSize records
+func bulkWriteBodyLimit(maxBatchSize int) int64 {
+	return int64(maxBatchSize)*bulkWriteBytesPerRecord + bodyOverheadBytes
+}
+
+// Largest body of a batch lookup of maxBatchSize codes
+func batchLookupBodyLimit(maxBatchSize int) int64 {
+	return int64(maxBatchSize)*batchLookupBytesPerCode + bodyOverheadBytes
+}
+
+// Reports whether err is caused by a body exceeding the limit set with http.MaxBytesReader
+func bodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
 // Handles GET /api/v1/swift-codes/{swift-code}
 func GetSwiftCodeHandler(w http.ResponseWriter, r *http.Request) {
 	prefix := "/api/v1/swift-codes/"
@@ -27,12 +64,11 @@ func GetSwiftCodeHandler(w http.ResponseWriter, r *http.Request) {
 
 	result, err := SwiftService.GetSwiftCodeDetails(r.Context(), swiftCode)
 	if err != nil || result == nil {
-		http.Error(w, "SWIFT code not found", http.StatusNotFound)
+		writeError(w, r, http.StatusNotFound, "SWIFT code not found")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(result)
+	writeConditional(w, r, result, codeLastModified(result), result.Version)
 }
 
 // Handles GET /api/v1/swift-codes/country/{countryISO2code}
@@ -51,18 +87,126 @@ func GetSwiftCodesByCountryHandler(w http.ResponseWriter, r *http.Request) {
 
 	results, err := SwiftService.GetSwiftCodesByCountry(r.Context(), countryCode)
 	if err != nil {
-		http.Error(w, "Error fetching SWIFT codes", http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, "Error fetching SWIFT codes")
+		return
+	}
+
+	// A country without SWIFT codes gets an empty list, as over gRPC
+	if results == nil {
+		results = &model.SwiftCodesByCountryResponse{
+			CountryISO2: strings.ToUpper(countryCode),
+			SwiftCodes:  []model.SwiftCodeMinimalResponse{},
+		}
+	}
+	// Lists are validated by their ETag only, as removed codes leave no date behind
+	writeConditional(w, r, results, time.Time{}, 0)
+}
+
+// Handles GET /api/v1/countries
+func ListCountriesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	result, err := SwiftService.ListCountries(r.Context())
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, "Error fetching countries")
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, result)
+}
+
+// Handles GET /api/v1/institutions/{bic4}
+func GetInstitutionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	prefix := "/api/v1/institutions/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	bic4 := strings.TrimPrefix(r.URL.Path, prefix)
+	if bic4 == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	result, err := SwiftService.GetInstitution(r.Context(), bic4)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidInput) {
+			writeError(w, r, http.StatusBadRequest, err.Error())
+		} else {
+			writeError(w, r, http.StatusInternalServerError, "Error fetching SWIFT codes")
+		}
+		return
+	}
+	if result == nil {
+		writeError(w, r, http.StatusNotFound, "Institution not found")
+		return
+	}
+
+	writeConditional(w, r, result, time.Time{}, 0)
+}
+
+// Handles GET /api/v1/stats
+func StatsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	result, err := SwiftService.GetStats(r.Context())
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, "Error computing statistics")
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, result)
+}
+
+// Handles GET /api/v1/stats/countries/{iso2}
+func CountryStatsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	prefix := "/api/v1/stats/countries/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	countryCode := strings.TrimPrefix(r.URL.Path, prefix)
+	if countryCode == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	result, err := SwiftService.GetCountryStats(r.Context(), countryCode)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, "Country not found")
+		} else {
+			writeError(w, r, http.StatusInternalServerError, "Error computing statistics")
+		}
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(results)
+	writeResponse(w, r, http.StatusOK, result)
 }
 
 // Handles POST /api/v1/swift-codes
 func CreateSwiftCodeHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if !acceptable(w, r) {
 		return
 	}
 
@@ -70,24 +214,221 @@ func CreateSwiftCodeHandler(w http.ResponseWriter, r *http.Request) {
 
 	var req model.CreateSwiftCodeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request data", http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, "Invalid request data")
 		return
 	}
 
 	if err := SwiftService.CreateSwiftCode(r.Context(), req); err != nil {
-		http.Error(w, fmt.Sprintf("Failed to create SWIFT code: %v", err), http.StatusInternalServerError)
+		if errors.Is(err, model.ErrInvalidInput) {
+			writeError(w, r, http.StatusBadRequest, err.Error())
+		} else {
+			writeError(w, r, http.StatusInternalServerError, fmt.Sprintf("Failed to create SWIFT code: %v", err))
+		}
+		return
+	}
+
+	writeResponse(w, r, http.StatusCreated, model.MessageResponse{Message: "SWIFT code created successfully"})
+}
+
+// Handles POST /api/v1/swift-codes/batch-lookup
+func BatchLookupHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	defer r.Body.Close()
+
+	maxBatchSize := SwiftService.MaxBatchSize()
+	r.Body = http.MaxBytesReader(w, r.Body, batchLookupBodyLimit(maxBatchSize))
+
+	var req model.BatchLookupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if bodyTooLarge(err) {
+			writeError(w, r, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body is too large for %d SWIFT codes", maxBatchSize))
+		} else {
+			writeError(w, r, http.StatusBadRequest, "Invalid request data")
+		}
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]string{"message": "SWIFT code created successfully"})
+	result, err := SwiftService.BatchLookup(r.Context(), req.SwiftCodes)
+	if err != nil {
+		if errors.Is(err, service.ErrBatchTooLarge) {
+			writeError(w, r, http.StatusBadRequest, err.Error())
+		} else {
+			writeError(w, r, http.StatusInternalServerError, "Error fetching SWIFT codes")
+		}
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, result)
+}
+
+// Handles POST /api/v1/swift-codes/bulk?mode=all-or-nothing|best-effort&upsert=true with a JSON array
+// or, with Content-Type application/x-ndjson, one JSON record per line
+func BulkWriteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if !acceptable(w, r) {
+		return
+	}
+
+	defer r.Body.Close()
+
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = model.BulkAllOrNothing
+	}
+	upsert := r.URL.Query().Get("upsert") == "true"
+
+	maxBatchSize := SwiftService.MaxBatchSize()
+	r.Body = http.MaxBytesReader(w, r.Body, bulkWriteBodyLimit(maxBatchSize))
+
+	reqs, err := decodeBulkRecords(r, maxBatchSize)
+	if err != nil {
+		if bodyTooLarge(err) {
+			writeError(w, r, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body is too large for %d records", maxBatchSize))
+		} else if errors.Is(err, service.ErrBatchTooLarge) {
+			writeError(w, r, http.StatusBadRequest, err.Error())
+		} else {
+			writeError(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid request data: %v", err))
+		}
+		return
+	}
+
+	result, err := SwiftService.BulkWrite(r.Context(), reqs, mode, upsert)
+	if err != nil {
+		if errors.Is(err, service.ErrBatchTooLarge) || errors.Is(err, service.ErrInvalidBulkMode) {
+			writeError(w, r, http.StatusBadRequest, err.Error())
+		} else {
+			writeError(w, r, http.StatusInternalServerError, "Failed to write SWIFT codes")
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if !result.Committed {
+		status = http.StatusUnprocessableEntity
+	}
+	writeResponse(w, r, status, result)
+}
+
+// Decodes the records of a bulk request from a JSON array or an NDJSON stream, one record at a time so that
+// decoding stops with service.ErrBatchTooLarge as soon as there are more than maxRecords
+func decodeBulkRecords(r *http.Request, maxRecords int) ([]model.CreateSwiftCodeRequest, error) {
+	decoder := json.NewDecoder(r.Body)
+	tooMany := fmt.Errorf("%w: more than %d records, at most %d are allowed", service.ErrBatchTooLarge, maxRecords, maxRecords)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/x-ndjson" && mediaType != "application/ndjson" && mediaType != "application/jsonl" {
+		if token, err := decoder.Token(); err != nil {
+			return nil, err
+		} else if token != json.Delim('[') {
+			return nil, fmt.Errorf("expected a JSON array of records")
+		}
+
+		reqs := []model.CreateSwiftCodeRequest{}
+		for decoder.More() {
+			if len(reqs) == maxRecords {
+				return nil, tooMany
+			}
+			var req model.CreateSwiftCodeRequest
+			if err := decoder.Decode(&req); err != nil {
+				return nil, fmt.Errorf("record %d: %w", len(reqs)+1, err)
+			}
+			reqs = append(reqs, req)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return reqs, nil
+	}
+
+	var reqs []model.CreateSwiftCodeRequest
+	for line := 1; ; line++ {
+		var req model.CreateSwiftCodeRequest
+		err := decoder.Decode(&req)
+		if err == io.EOF {
+			return reqs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", line, err)
+		}
+		if len(reqs) == maxRecords {
+			return nil, tooMany
+		}
+		reqs = append(reqs, req)
+	}
+}
+
+// Handles PUT /api/v1/swift-codes/{swift-code}, responding with the updated SWIFT code and its new ETag
+func UpdateSwiftCodeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if !acceptable(w, r) {
+		return
+	}
+
+	prefix := "/api/v1/swift-codes/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+
+	swiftCode := strings.TrimPrefix(r.URL.Path, prefix)
+	if swiftCode == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
+	defer r.Body.Close()
+
+	var req model.UpdateSwiftCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid request data")
+		return
+	}
+
+	err := SwiftService.UpdateSwiftCode(r.Context(), swiftCode, req, version)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, "SWIFT code not found")
+		} else if errors.Is(err, model.ErrVersionMismatch) {
+			writeError(w, r, http.StatusPreconditionFailed, "SWIFT code has been modified")
+		} else if errors.Is(err, model.ErrInvalidInput) {
+			writeError(w, r, http.StatusBadRequest, err.Error())
+		} else {
+			writeError(w, r, http.StatusInternalServerError, fmt.Sprintf("Failed to update SWIFT code: %v", err))
+		}
+		return
+	}
+
+	result, err := SwiftService.GetSwiftCodeDetails(r.Context(), swiftCode)
+	if err != nil || result == nil {
+		writeError(w, r, http.StatusInternalServerError, "Error fetching SWIFT code")
+		return
+	}
+
+	writeConditional(w, r, result, codeLastModified(result), result.Version)
 }
 
 // Handles DELETE /api/v1/swift-codes/{swift-code}
 func DeleteSwiftCodeHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if !acceptable(w, r) {
 		return
 	}
 
@@ -103,16 +444,31 @@ func DeleteSwiftCodeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := SwiftService.DeleteSwiftCode(r.Context(), swiftCode)
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
+	err := SwiftService.DeleteSwiftCode(r.Context(), swiftCode, version)
 	if err != nil {
-		if strings.Contains(err.Error(), "no SWIFT code found") {
-			http.Error(w, "SWIFT code not found", http.StatusNotFound)
+		if errors.Is(err, model.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, "SWIFT code not found")
+		} else if errors.Is(err, model.ErrVersionMismatch) {
+			writeError(w, r, http.StatusPreconditionFailed, "SWIFT code has been modified")
 		} else {
-			http.Error(w, fmt.Sprintf("Failed to delete SWIFT code: %v", err), http.StatusInternalServerError)
+			writeError(w, r, http.StatusInternalServerError, fmt.Sprintf("Failed to delete SWIFT code: %v", err))
 		}
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"message": "SWIFT code deleted successfully"})
+	writeResponse(w, r, http.StatusOK, model.MessageResponse{Message: "SWIFT code deleted successfully"})
+}
+
+// Returns the Last-Modified date of a SWIFT code response, zero for headquarters as deleting one of
+// their branches does not change any date
+func codeLastModified(result *model.SwiftCodeResponse) time.Time {
+	if result.IsHeadquarter {
+		return time.Time{}
+	}
+	return result.LastModified
 }
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/dodskygge/go_swift/internal/model"
 	"github.com/dodskygge/go_swift/internal/service"
@@ -35,6 +36,14 @@ func (m *MockSwiftCodeService) GetBranchesByHqSwiftCode(ctx context.Context, hqC
 	return args.Get(0).([]*model.SwiftEntity), args.Error(1)
 }
 
+func (m *MockSwiftCodeService) GetBranchesByHqSwiftCodes(ctx context.Context, hqCodes []string) ([]*model.SwiftEntity, error) {
+	args := m.Called(ctx, hqCodes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SwiftEntity), args.Error(1)
+}
+
 func (m *MockSwiftCodeService) GetByCountry(ctx context.Context, countryISO2 string) ([]*model.SwiftEntity, error) {
 	args := m.Called(ctx, countryISO2)
 	if args.Get(0) == nil {
@@ -43,16 +52,93 @@ func (m *MockSwiftCodeService) GetByCountry(ctx context.Context, countryISO2 str
 	return args.Get(0).([]*model.SwiftEntity), args.Error(1)
 }
 
+func (m *MockSwiftCodeService) GetBySwiftCodes(ctx context.Context, swiftCodes []string) ([]*model.SwiftEntity, error) {
+	args := m.Called(ctx, swiftCodes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SwiftEntity), args.Error(1)
+}
+
+func (m *MockSwiftCodeService) Search(ctx context.Context, search model.SwiftCodeSearch) ([]*model.SwiftEntity, error) {
+	args := m.Called(ctx, search)
+	entities, _ := args.Get(0).([]*model.SwiftEntity)
+	return entities, args.Error(1)
+}
+
+func (m *MockSwiftCodeService) ForEach(ctx context.Context, filter model.SwiftCodeFilter, fn func(*model.SwiftEntity) error) error {
+	args := m.Called(ctx, filter, fn)
+	if entities, ok := args.Get(0).([]*model.SwiftEntity); ok {
+		for _, entity := range entities {
+			if err := fn(entity); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
 func (m *MockSwiftCodeService) Create(ctx context.Context, swift *model.SwiftEntity) error {
 	args := m.Called(ctx, swift)
 	return args.Error(0)
 }
 
-func (m *MockSwiftCodeService) Delete(ctx context.Context, swiftCode string) error {
-	args := m.Called(ctx, swiftCode)
+func (m *MockSwiftCodeService) BulkWrite(ctx context.Context, entities []*model.SwiftEntity, upsert, atomic bool) ([]model.BulkItemResult, bool, error) {
+	args := m.Called(ctx, entities, upsert, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).([]model.BulkItemResult), args.Bool(1), args.Error(2)
+}
+
+func (m *MockSwiftCodeService) Update(ctx context.Context, swift *model.SwiftEntity, expectedVersion int64) error {
+	args := m.Called(ctx, swift, expectedVersion)
+	return args.Error(0)
+}
+
+func (m *MockSwiftCodeService) Delete(ctx context.Context, swiftCode string, expectedVersion int64) error {
+	args := m.Called(ctx, swiftCode, expectedVersion)
 	return args.Error(0)
 }
 
+func (m *MockSwiftCodeService) ListCountries(ctx context.Context) ([]*model.Country, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Country), args.Error(1)
+}
+
+func (m *MockSwiftCodeService) Stats(ctx context.Context, countryISO2 string) (*model.SwiftCodeStats, error) {
+	args := m.Called(ctx, countryISO2)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SwiftCodeStats), args.Error(1)
+}
+
+func (m *MockSwiftCodeService) GetAuditLog(ctx context.Context, query model.AuditQuery) ([]model.AuditEntry, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AuditEntry), args.Error(1)
+}
+
+func (m *MockSwiftCodeService) GetByInstitution(ctx context.Context, bic4 string) ([]*model.SwiftEntity, error) {
+	args := m.Called(ctx, bic4)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SwiftEntity), args.Error(1)
+}
+
+// Reference countries returned by the mock repository
+var testCountries = []*model.Country{
+	{ISO2: "PL", ISO3: "POL", Numeric: "616", Name: "Poland"},
+	{ISO2: "US", ISO3: "USA", Numeric: "840", Name: "United States"},
+}
+
 func TestGetSwiftCodeHandler(t *testing.T) {
 	mockService := new(MockSwiftCodeService)
 	SwiftService = service.NewSwiftCodeService(mockService)
@@ -192,6 +278,7 @@ func TestCreateSwiftCodeHandler(t *testing.T) {
 	}
 
 	// Define mock behavior
+	mockService.On("ListCountries", mock.Anything).Return(testCountries, nil)
 	mockService.On("Create", mock.Anything, expectedEntity).Return(nil)
 
 	// Create request and recorder
@@ -209,6 +296,101 @@ func TestCreateSwiftCodeHandler(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "SWIFT code created successfully", response["message"])
 
+	// Countries outside the reference table are rejected
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes", strings.NewReader(strings.Replace(requestBody, `"US"`, `"XX"`, 1)))
+	rec = httptest.NewRecorder()
+	CreateSwiftCodeHandler(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `countryISO2 \"XX\" is not an ISO 3166 country code`)
+
+	mockService.AssertExpectations(t)
+}
+
+// Unit test for GetInstitutionHandler
+func TestGetInstitutionHandler(t *testing.T) {
+	mockService := new(MockSwiftCodeService)
+	SwiftService = service.NewSwiftCodeService(mockService)
+
+	mockService.On("GetByInstitution", mock.Anything, "TPEO").Return([]*model.SwiftEntity{
+		{SwiftCode: "TPEOPLPWXXX", BankName: "Pekao TFI", Address: "1 Prosta", CountryISO2: "PL", CountryName: "Poland", IsHeadquarter: true},
+	}, nil)
+	mockService.On("GetByInstitution", mock.Anything, "NONE").Return(nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/institutions/TPEO", nil)
+	rec := httptest.NewRecorder()
+	GetInstitutionHandler(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("ETag"))
+	assert.JSONEq(t, `{"institutionCode":"TPEO","countries":[{"countryISO2":"PL","countryName":"POLAND","swiftCodes":[
+		{"address":"1 Prosta","bankName":"Pekao TFI","countryISO2":"PL","isHeadquarter":true,"swiftCode":"TPEOPLPWXXX"}]}]}`, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/institutions/NONE", nil)
+	rec = httptest.NewRecorder()
+	GetInstitutionHandler(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/institutions/TPEOPLPW", nil)
+	rec = httptest.NewRecorder()
+	GetInstitutionHandler(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+// Unit test for StatsHandler and CountryStatsHandler
+func TestStatsHandlers(t *testing.T) {
+	mockService := new(MockSwiftCodeService)
+	SwiftService = service.NewSwiftCodeService(mockService)
+
+	lastUpdated := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	mockService.On("Stats", mock.Anything, "").Return(&model.SwiftCodeStats{
+		Countries: 2, SwiftCodes: 4, Headquarters: 3, Branches: 1, Institutions: 2, Towns: 2, LastUpdated: &lastUpdated,
+	}, nil)
+	mockService.On("ListCountries", mock.Anything).Return(testCountries, nil)
+	mockService.On("Stats", mock.Anything, "PL").Return(&model.SwiftCodeStats{Countries: 0}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stats", nil)
+	rec := httptest.NewRecorder()
+	StatsHandler(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"countries":2,"swiftCodes":4,"headquarters":3,"branches":1,"institutions":2,"towns":2,"lastUpdated":"2025-04-01T10:00:00Z"}`, rec.Body.String())
+
+	// Countries without codes have zero counts and no last update
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/stats/countries/pl", nil)
+	rec = httptest.NewRecorder()
+	CountryStatsHandler(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"countryISO2":"PL","countryName":"POLAND","swiftCodes":0,"headquarters":0,"branches":0,"institutions":0,"towns":0}`, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/stats/countries/XX", nil)
+	rec = httptest.NewRecorder()
+	CountryStatsHandler(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+// Unit test for ListCountriesHandler
+func TestListCountriesHandler(t *testing.T) {
+	mockService := new(MockSwiftCodeService)
+	SwiftService = service.NewSwiftCodeService(mockService)
+
+	mockService.On("ListCountries", mock.Anything).Return([]*model.Country{
+		{ISO2: "PL", ISO3: "POL", Numeric: "616", Name: "Poland", SwiftCodeCount: 459},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/countries", nil)
+	rec := httptest.NewRecorder()
+	ListCountriesHandler(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"countries":[{"iso2":"PL","iso3":"POL","numeric":"616","name":"Poland","swiftCodeCount":459}]}`, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/countries", nil)
+	rec = httptest.NewRecorder()
+	ListCountriesHandler(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
 	mockService.AssertExpectations(t)
 }
 
@@ -216,10 +398,11 @@ func TestDeleteSwiftCodeHandler(t *testing.T) {
 	mockService := new(MockSwiftCodeService)
 	SwiftService = service.NewSwiftCodeService(mockService)
 
-	mockService.On("Delete", mock.Anything, "TESTUS33XXX").Return(nil)
+	mockService.On("Delete", mock.Anything, "TESTUS33XXX", int64(4)).Return(nil)
 
 	// Create request and recorder
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/swift-codes/TESTUS33XXX", nil)
+	req.Header.Set("If-Match", `"4-0123456789abcdef0123456789abcdef"`)
 	rec := httptest.NewRecorder()
 
 	// Call handler
@@ -234,3 +417,179 @@ func TestDeleteSwiftCodeHandler(t *testing.T) {
 
 	mockService.AssertExpectations(t)
 }
+
+func TestDeleteSwiftCodeHandlerPreconditions(t *testing.T) {
+	mockService := new(MockSwiftCodeService)
+	SwiftService = service.NewSwiftCodeService(mockService)
+
+	mockService.On("Delete", mock.Anything, "TESTUS33XXX", int64(3)).Return(model.ErrVersionMismatch)
+
+	// Missing If-Match
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/swift-codes/TESTUS33XXX", nil)
+	rec := httptest.NewRecorder()
+	DeleteSwiftCodeHandler(rec, req)
+	assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+
+	// Entity tag of the country listing, which carries no version
+	req.Header.Set("If-Match", `"0123456789abcdef0123456789abcdef"`)
+	rec = httptest.NewRecorder()
+	DeleteSwiftCodeHandler(rec, req)
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	// Stale version
+	req.Header.Set("If-Match", `"3-0123456789abcdef0123456789abcdef"`)
+	rec = httptest.NewRecorder()
+	DeleteSwiftCodeHandler(rec, req)
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateSwiftCodeHandler(t *testing.T) {
+	mockService := new(MockSwiftCodeService)
+	SwiftService = service.NewSwiftCodeService(mockService)
+
+	expectedEntity := &model.SwiftEntity{
+		SwiftCode:   "TESTUS33ABC",
+		BankName:    "Renamed Branch",
+		Address:     "1 New St",
+		CountryISO2: "US",
+		CountryName: "UNITED STATES",
+	}
+	mockService.On("ListCountries", mock.Anything).Return(testCountries, nil)
+	mockService.On("Update", mock.Anything, expectedEntity, int64(1)).Return(nil)
+	mockService.On("GetBySwiftCode", mock.Anything, "TESTUS33ABC").Return(&model.SwiftEntity{
+		SwiftCode:   "TESTUS33ABC",
+		BankName:    "Renamed Branch",
+		Address:     "1 New St",
+		CountryISO2: "US",
+		CountryName: "UNITED STATES",
+		Version:     2,
+	}, nil)
+
+	requestBody := `{"address": "1 New St", "bankName": "Renamed Branch", "countryISO2": "US", "countryName": "United States"}`
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/swift-codes/TESTUS33ABC", strings.NewReader(requestBody))
+	req.Header.Set("If-Match", `"1-0123456789abcdef0123456789abcdef"`)
+	rec := httptest.NewRecorder()
+
+	UpdateSwiftCodeHandler(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Regexp(t, `^"2-[0-9a-f]{32}"$`, rec.Header().Get("ETag"))
+	var response model.SwiftCodeResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "Renamed Branch", response.BankName)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestBatchLookupHandler(t *testing.T) {
+	mockService := new(MockSwiftCodeService)
+	SwiftService = service.NewSwiftCodeService(mockService, service.WithMaxBatchSize(3))
+
+	mockService.On("GetBySwiftCodes", mock.Anything, []string{"TESTUS33XXX", "MISSPLPWXXX"}).Return([]*model.SwiftEntity{
+		{SwiftCode: "TESTUS33XXX", BankName: "Test Bank", Address: "123 Main St", CountryISO2: "US", CountryName: "United States", IsHeadquarter: true},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes/batch-lookup", strings.NewReader(`{"swiftCodes":["TESTUS33XXX","MISSPLPWXXX","BAD"]}`))
+	rec := httptest.NewRecorder()
+	BatchLookupHandler(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response model.BatchLookupResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(t, response.Found, 1)
+	assert.Equal(t, "Test Bank", response.Found[0].BankName)
+	assert.Equal(t, []string{"MISSPLPWXXX"}, response.NotFound)
+	assert.Equal(t, []string{"BAD"}, response.Invalid)
+
+	// Over the limit
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes/batch-lookup", strings.NewReader(`{"swiftCodes":["A","B","C","D"]}`))
+	rec = httptest.NewRecorder()
+	BatchLookupHandler(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Bodies too large for the limit are not read whole
+	body := `{"swiftCodes":["` + strings.Repeat("A", int(batchLookupBodyLimit(3))) + `"]}`
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes/batch-lookup", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	BatchLookupHandler(rec, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestBulkWriteHandler(t *testing.T) {
+	mockService := new(MockSwiftCodeService)
+	SwiftService = service.NewSwiftCodeService(mockService)
+
+	mockService.On("ListCountries", mock.Anything).Return(testCountries, nil)
+	mockService.On("BulkWrite", mock.Anything, mock.AnythingOfType("[]*model.SwiftEntity"), false, true).Return([]model.BulkItemResult{
+		{Index: 0, SwiftCode: "TESTUS33XXX", Status: model.BulkStatusCreated},
+		{Index: 1, SwiftCode: "TESTPLPWXXX", Status: model.BulkStatusCreated},
+	}, true, nil)
+
+	// NDJSON stream
+	body := `{"address":"123 Main St","bankName":"Test Bank","countryISO2":"US","countryName":"United States","isHeadquarter":true,"swiftCode":"TESTUS33XXX"}
+{"address":"1 Prosta","bankName":"Test Bank PL","countryISO2":"PL","countryName":"Poland","isHeadquarter":true,"swiftCode":"TESTPLPWXXX"}
+`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes/bulk", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	rec := httptest.NewRecorder()
+	BulkWriteHandler(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response model.BulkWriteResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.True(t, response.Committed)
+	assert.Equal(t, 2, response.Created)
+
+	// JSON array with an invalid record in all-or-nothing mode
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes/bulk?mode=all-or-nothing", strings.NewReader(`[{"swiftCode":"BAD"}]`))
+	rec = httptest.NewRecorder()
+	BulkWriteHandler(rec, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	// Malformed body and unknown mode
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes/bulk", strings.NewReader(`{"swiftCode":"TESTUS33XXX"}`))
+	rec = httptest.NewRecorder()
+	BulkWriteHandler(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes/bulk?mode=yolo", strings.NewReader(`[]`))
+	rec = httptest.NewRecorder()
+	BulkWriteHandler(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+// Unit test for the record and body limits of BulkWriteHandler
+func TestBulkWriteHandlerLimits(t *testing.T) {
+	mockService := new(MockSwiftCodeService)
+	SwiftService = service.NewSwiftCodeService(mockService, service.WithMaxBatchSize(2))
+
+	record := `{"address":"123 Main St","bankName":"Test Bank","countryISO2":"US","countryName":"United States","isHeadquarter":true,"swiftCode":"TESTUS33XXX"}`
+
+	// Decoding stops at the first record past the limit, whatever follows it
+	for contentType, body := range map[string]string{
+		"application/json":     "[" + strings.Repeat(record+",", 3) + "garbage",
+		"application/x-ndjson": strings.Repeat(record+"\n", 3) + "garbage",
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes/bulk", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		BulkWriteHandler(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, contentType)
+		assert.Contains(t, rec.Body.String(), "more than 2 records", contentType)
+	}
+
+	// Bodies too large for the limit are not read whole
+	body := `[{"bankName":"` + strings.Repeat("A", int(bulkWriteBodyLimit(2))) + `"}]`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes/bulk", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	BulkWriteHandler(rec, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
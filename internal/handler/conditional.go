@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dodskygge/go_swift/internal/respond"
+)
+
+// Writes body in the media type negotiated from the Accept header with a strong ETag and Last-Modified
+// header, answering 304 Not Modified when the client's cached copy is still current. A non-zero version
+// is embedded in the ETag so it can be sent back in If-Match.
+func writeConditional(w http.ResponseWriter, r *http.Request, body any, lastModified time.Time, version int64) {
+	mediaType, ok := respond.Negotiate(r)
+	if !ok {
+		writeError(w, r, http.StatusNotAcceptable, respond.NotAcceptableMessage)
+		return
+	}
+
+	data, err := respond.Encode(mediaType, body)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, "Error encoding response")
+		return
+	}
+
+	// The ETag is computed over the encoded body, so each representation has its own
+	h := w.Header()
+	h.Add("Vary", "Accept")
+	h.Set("ETag", contentETag(data, version))
+	if !lastModified.IsZero() {
+		h.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if (r.Method == http.MethodGet || r.Method == http.MethodHead) && notModified(r, h.Get("ETag"), lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	h.Set("Content-Type", mediaType)
+	w.Write(data)
+}
+
+// Returns a strong entity tag derived from the response body, prefixed with the version when set
+// so that it changes both when the record and when related data such as branches change
+func contentETag(data []byte, version int64) string {
+	sum := sha256.Sum256(data)
+	if version > 0 {
+		return `"` + strconv.FormatInt(version, 10) + "-" + hex.EncodeToString(sum[:16]) + `"`
+	}
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// Returns the version a mutating request is based on from its If-Match header, 0 for "*".
+// It writes 428 Precondition Required when the header is missing and 412 Precondition Failed
+// when it holds no entity tag issued by this API, returning false in both cases.
+func ifMatchVersion(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" {
+		writeError(w, r, http.StatusPreconditionRequired, "If-Match header is required, send the ETag of the SWIFT code")
+		return 0, false
+	}
+	if ifMatch == "*" {
+		return 0, true
+	}
+
+	// Weak tags never match in If-Match, and a list is only meaningful for a single current version
+	tag, _, _ := strings.Cut(ifMatch, ",")
+	tag = strings.TrimSpace(tag)
+	if strings.HasPrefix(tag, `"`) && strings.HasSuffix(tag, `"`) && len(tag) > 2 {
+		prefix, _, found := strings.Cut(tag[1:len(tag)-1], "-")
+		if version, err := strconv.ParseInt(prefix, 10, 64); found && err == nil && version > 0 {
+			return version, true
+		}
+	}
+
+	writeError(w, r, http.StatusPreconditionFailed, "SWIFT code has been modified")
+	return 0, false
+}
+
+// Evaluates If-None-Match and If-Modified-Since; If-Modified-Since is ignored when If-None-Match is present
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, etag, true)
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	// HTTP dates have second precision
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// Reports whether a comma-separated list of entity tags from If-None-Match or If-Match contains etag,
+// comparing weak tags as equal to strong ones when weak is set
+func etagMatches(list, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
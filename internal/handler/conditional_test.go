@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/dodskygge/go_swift/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Unit test for conditional GET of a single SWIFT code
+func TestGetSwiftCodeHandlerConditional(t *testing.T) {
+	mockService := new(MockSwiftCodeService)
+	SwiftService = service.NewSwiftCodeService(mockService)
+
+	updatedAt := time.Date(2025, 4, 23, 15, 30, 0, 0, time.UTC)
+	mockService.On("GetBySwiftCode", mock.Anything, "TESTUS33ABC").Return(&model.SwiftEntity{
+		Address:     "456 Branch St",
+		BankName:    "Test Bank Branch",
+		CountryISO2: "US",
+		CountryName: "United States",
+		SwiftCode:   "TESTUS33ABC",
+		UpdatedAt:   updatedAt,
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/TESTUS33ABC", nil)
+	rec := httptest.NewRecorder()
+	GetSwiftCodeHandler(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, "Wed, 23 Apr 2025 15:30:00 GMT", rec.Header().Get("Last-Modified"))
+
+	// Matching entity tag
+	req.Header.Set("If-None-Match", `"other", `+etag)
+	rec = httptest.NewRecorder()
+	GetSwiftCodeHandler(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+	assert.Equal(t, etag, rec.Header().Get("ETag"))
+
+	// A stale entity tag wins over a current If-Modified-Since
+	req.Header.Set("If-None-Match", `"other"`)
+	req.Header.Set("If-Modified-Since", "Wed, 23 Apr 2025 16:00:00 GMT")
+	rec = httptest.NewRecorder()
+	GetSwiftCodeHandler(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Only If-Modified-Since
+	req.Header.Del("If-None-Match")
+	rec = httptest.NewRecorder()
+	GetSwiftCodeHandler(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+
+	req.Header.Set("If-Modified-Since", "Wed, 23 Apr 2025 15:29:59 GMT")
+	rec = httptest.NewRecorder()
+	GetSwiftCodeHandler(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+// Unit test for responses listing several codes, sent without Last-Modified
+func TestConditionalWithoutLastModified(t *testing.T) {
+	mockService := new(MockSwiftCodeService)
+	SwiftService = service.NewSwiftCodeService(mockService)
+
+	updatedAt := time.Date(2025, 4, 23, 15, 30, 0, 0, time.UTC)
+	hq := &model.SwiftEntity{SwiftCode: "TESTUS33XXX", BankName: "Test Bank", CountryISO2: "US", CountryName: "United States", IsHeadquarter: true, UpdatedAt: updatedAt}
+	mockService.On("GetBySwiftCode", mock.Anything, "TESTUS33XXX").Return(hq, nil)
+	mockService.On("GetBranchesByHqSwiftCode", mock.Anything, "TESTUS33").Return([]*model.SwiftEntity{}, nil)
+	mockService.On("GetByCountry", mock.Anything, "US").Return([]*model.SwiftEntity{hq}, nil)
+
+	// Deleting a branch changes no date, so a headquarters is validated by its ETag only
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/TESTUS33XXX", nil)
+	req.Header.Set("If-Modified-Since", "Wed, 23 Apr 2025 16:00:00 GMT")
+	rec := httptest.NewRecorder()
+	GetSwiftCodeHandler(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Last-Modified"))
+	assert.NotEmpty(t, rec.Header().Get("ETag"))
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/country/US", nil)
+	req.Header.Set("If-Modified-Since", "Wed, 23 Apr 2025 16:00:00 GMT")
+	rec = httptest.NewRecorder()
+	GetSwiftCodesByCountryHandler(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Last-Modified"))
+	assert.NotEmpty(t, rec.Header().Get("ETag"))
+
+	mockService.AssertExpectations(t)
+}
+
+// Unit test for etagMatches
+func TestETagMatches(t *testing.T) {
+	assert.True(t, etagMatches(`"a", "b"`, `"b"`, false))
+	assert.True(t, etagMatches(`*`, `"b"`, false))
+	assert.True(t, etagMatches(`W/"b"`, `"b"`, true))
+	assert.False(t, etagMatches(`W/"b"`, `"b"`, false))
+	assert.False(t, etagMatches(`"a"`, `"b"`, true))
+}
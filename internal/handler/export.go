@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dodskygge/go_swift/internal/model"
+)
+
+// Number of records written between flushes of an export stream
+const exportFlushEvery = 500
+
+// Column layout of CSV exports, matching the fields of the import format
+var exportCSVHeader = []string{"swiftCode", "bankName", "address", "countryISO2", "countryName", "isHeadquarter"}
+
+// Writes exported records in one output format
+type exportWriter interface {
+	begin() error
+	write(record model.CreateSwiftCodeRequest) error
+	end() error
+	// flush hands records buffered by the writer itself to the underlying stream
+	flush() error
+}
+
+// Handles GET /api/v1/swift-codes/export?format=csv|json|ndjson&country={countryISO2code}&hq=true|false,
+// streaming every matching SWIFT code in the import format without buffering the whole table
+func ExportSwiftCodesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+
+	filter := model.SwiftCodeFilter{CountryISO2: query.Get("country")}
+	if hq := query.Get("hq"); hq != "" {
+		isHeadquarter, err := strconv.ParseBool(hq)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "Invalid hq filter, expected true or false")
+			return
+		}
+		filter.IsHeadquarter = &isHeadquarter
+	}
+
+	format := strings.ToLower(query.Get("format"))
+	if format == "" {
+		format = "json"
+	}
+
+	buf := bufio.NewWriter(w)
+	var out exportWriter
+	var contentType string
+	switch format {
+	case "csv":
+		out, contentType = &csvExportWriter{w: csv.NewWriter(buf)}, "text/csv; charset=utf-8"
+	case "json":
+		out, contentType = &jsonExportWriter{w: buf}, "application/json"
+	case "ndjson":
+		out, contentType = &ndjsonExportWriter{enc: json.NewEncoder(buf)}, "application/x-ndjson"
+	default:
+		writeError(w, r, http.StatusBadRequest, "Invalid format, expected csv, json or ndjson")
+		return
+	}
+
+	controller := http.NewResponseController(w)
+	flush := func() error {
+		if err := out.flush(); err != nil {
+			return err
+		}
+		if err := buf.Flush(); err != nil {
+			return err
+		}
+		if err := controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		return nil
+	}
+
+	// Headers are sent with the first record so that a failure before it can still be reported
+	started := false
+	start := func() error {
+		started = true
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="swift-codes.`+format+`"`)
+		return out.begin()
+	}
+
+	count := 0
+	err := SwiftService.ExportSwiftCodes(r.Context(), filter, func(record model.CreateSwiftCodeRequest) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := out.write(record); err != nil {
+			return err
+		}
+		count++
+		if count%exportFlushEvery == 0 {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if err == nil {
+		err = out.end()
+	}
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		if !started {
+			writeError(w, r, http.StatusInternalServerError, "Error exporting SWIFT codes")
+			return
+		}
+		// Part of the export may already be on the wire, so abort the connection rather than end the body cleanly
+		slog.ErrorContext(r.Context(), "export aborted", "format", format, "records", count, "error", err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// Writes a CSV header followed by one row per record
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvExportWriter) begin() error {
+	return c.w.Write(exportCSVHeader)
+}
+
+func (c *csvExportWriter) write(record model.CreateSwiftCodeRequest) error {
+	return c.w.Write([]string{
+		record.SwiftCode,
+		record.BankName,
+		record.Address,
+		record.CountryISO2,
+		record.CountryName,
+		strconv.FormatBool(record.IsHeadquarter),
+	})
+}
+
+func (c *csvExportWriter) end() error {
+	return nil
+}
+
+func (c *csvExportWriter) flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// Writes the records as elements of a single JSON array
+type jsonExportWriter struct {
+	w       *bufio.Writer
+	written bool
+}
+
+func (j *jsonExportWriter) begin() error {
+	_, err := j.w.WriteString("[")
+	return err
+}
+
+func (j *jsonExportWriter) write(record model.CreateSwiftCodeRequest) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if j.written {
+		if _, err := j.w.WriteString(","); err != nil {
+			return err
+		}
+	}
+	j.written = true
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *jsonExportWriter) end() error {
+	_, err := j.w.WriteString("]\n")
+	return err
+}
+
+func (j *jsonExportWriter) flush() error {
+	return nil
+}
+
+// Writes one JSON object per line
+type ndjsonExportWriter struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonExportWriter) begin() error {
+	return nil
+}
+
+func (n *ndjsonExportWriter) write(record model.CreateSwiftCodeRequest) error {
+	return n.enc.Encode(record)
+}
+
+func (n *ndjsonExportWriter) end() error {
+	return nil
+}
+
+func (n *ndjsonExportWriter) flush() error {
+	return nil
+}
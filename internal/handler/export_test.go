@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/dodskygge/go_swift/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var exportEntities = []*model.SwiftEntity{
+	{SwiftCode: "TESTUS33XXX", BankName: "Test Bank", Address: "123 Main St, Suite 1", CountryISO2: "US", CountryName: "United States", IsHeadquarter: true},
+	{SwiftCode: "TESTUS33ABC", BankName: "Test Bank", Address: "456 Side St", CountryISO2: "US", CountryName: "United States", IsHeadquarter: false},
+}
+
+// Unit test for ExportSwiftCodesHandler in each format
+func TestExportSwiftCodesHandler(t *testing.T) {
+	mockService := new(MockSwiftCodeService)
+	SwiftService = service.NewSwiftCodeService(mockService)
+
+	mockService.On("ForEach", mock.Anything, model.SwiftCodeFilter{CountryISO2: "US"}, mock.Anything).Return(exportEntities, nil)
+
+	tests := []struct {
+		format      string
+		contentType string
+		body        string
+	}{
+		{
+			format:      "csv",
+			contentType: "text/csv; charset=utf-8",
+			body: "swiftCode,bankName,address,countryISO2,countryName,isHeadquarter\n" +
+				"TESTUS33XXX,Test Bank,\"123 Main St, Suite 1\",US,UNITED STATES,true\n" +
+				"TESTUS33ABC,Test Bank,456 Side St,US,UNITED STATES,false\n",
+		},
+		{
+			format:      "json",
+			contentType: "application/json",
+			body: `[{"address":"123 Main St, Suite 1","bankName":"Test Bank","countryISO2":"US","countryName":"UNITED STATES","isHeadquarter":true,"swiftCode":"TESTUS33XXX"},` +
+				`{"address":"456 Side St","bankName":"Test Bank","countryISO2":"US","countryName":"UNITED STATES","isHeadquarter":false,"swiftCode":"TESTUS33ABC"}]` + "\n",
+		},
+		{
+			format:      "ndjson",
+			contentType: "application/x-ndjson",
+			body: `{"address":"123 Main St, Suite 1","bankName":"Test Bank","countryISO2":"US","countryName":"UNITED STATES","isHeadquarter":true,"swiftCode":"TESTUS33XXX"}` + "\n" +
+				`{"address":"456 Side St","bankName":"Test Bank","countryISO2":"US","countryName":"UNITED STATES","isHeadquarter":false,"swiftCode":"TESTUS33ABC"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/export?format="+tt.format+"&country=us", nil)
+			rec := httptest.NewRecorder()
+			ExportSwiftCodesHandler(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.contentType, rec.Header().Get("Content-Type"))
+			assert.Equal(t, `attachment; filename="swift-codes.`+tt.format+`"`, rec.Header().Get("Content-Disposition"))
+			assert.Equal(t, tt.body, rec.Body.String())
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+// Unit test for ExportSwiftCodesHandler filters, empty results and errors
+func TestExportSwiftCodesHandlerFilters(t *testing.T) {
+	mockService := new(MockSwiftCodeService)
+	SwiftService = service.NewSwiftCodeService(mockService)
+
+	hq := false
+	mockService.On("ForEach", mock.Anything, model.SwiftCodeFilter{IsHeadquarter: &hq}, mock.Anything).Return(nil, nil)
+	mockService.On("ForEach", mock.Anything, model.SwiftCodeFilter{CountryISO2: "PL"}, mock.Anything).Return(nil, errors.New("db down"))
+
+	// An empty export is still a valid document
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/export?hq=false", nil)
+	rec := httptest.NewRecorder()
+	ExportSwiftCodesHandler(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "[]\n", rec.Body.String())
+
+	// Errors before the first record are reported
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/export?format=csv&country=PL", nil)
+	rec = httptest.NewRecorder()
+	ExportSwiftCodesHandler(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	for _, query := range []string{"format=xml", "hq=maybe"} {
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/export?"+query, nil)
+		rec = httptest.NewRecorder()
+		ExportSwiftCodesHandler(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/dodskygge/go_swift/internal/logging"
+)
+
+// LogLevel controls the minimum level of the application logger at runtime.
+var LogLevel = new(slog.LevelVar)
+
+type logLevelBody struct {
+	Level string `json:"level"`
+}
+
+// Handles GET and PUT /api/v1/admin/log-level
+func LogLevelHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		defer r.Body.Close()
+
+		var body logLevelBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, r, http.StatusBadRequest, "Invalid request data")
+			return
+		}
+
+		level, err := logging.ParseLevel(body.Level)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		LogLevel.Set(level)
+		slog.InfoContext(r.Context(), "log level changed", "level", level.String())
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(logLevelBody{Level: LogLevel.Level().String()})
+}
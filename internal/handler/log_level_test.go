@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogLevelHandler(t *testing.T) {
+	LogLevel.Set(slog.LevelInfo)
+	defer LogLevel.Set(slog.LevelInfo)
+
+	// Read the current level
+	rec := httptest.NewRecorder()
+	LogLevelHandler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/log-level", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"level":"INFO"}`, rec.Body.String())
+
+	// Change the level
+	rec = httptest.NewRecorder()
+	LogLevelHandler(rec, httptest.NewRequest(http.MethodPut, "/api/v1/admin/log-level", strings.NewReader(`{"level":"debug"}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response map[string]string
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "DEBUG", response["level"])
+	assert.Equal(t, slog.LevelDebug, LogLevel.Level())
+
+	// Reject an unknown level
+	rec = httptest.NewRecorder()
+	LogLevelHandler(rec, httptest.NewRequest(http.MethodPut, "/api/v1/admin/log-level", strings.NewReader(`{"level":"verbose"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, slog.LevelDebug, LogLevel.Level())
+}
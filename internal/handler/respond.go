@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/dodskygge/go_swift/internal/respond"
+)
+
+// Writes 406 Not Acceptable and returns false unless the client accepts a type responses can be rendered in.
+// Handlers that change data call it before doing so, because their response could not be delivered afterwards.
+func acceptable(w http.ResponseWriter, r *http.Request) bool {
+	if _, ok := respond.Negotiate(r); !ok {
+		writeError(w, r, http.StatusNotAcceptable, respond.NotAcceptableMessage)
+		return false
+	}
+	return true
+}
+
+// Writes body with the given status in the media type negotiated from the Accept header,
+// answering 406 Not Acceptable when the client accepts none of them
+func writeResponse(w http.ResponseWriter, r *http.Request, status int, body any) {
+	mediaType, ok := respond.Negotiate(r)
+	if !ok {
+		writeError(w, r, http.StatusNotAcceptable, respond.NotAcceptableMessage)
+		return
+	}
+
+	data, err := respond.Encode(mediaType, body)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, "Error encoding response")
+		return
+	}
+
+	w.Header().Add("Vary", "Accept")
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// WriteError writes an error response in the format of the handlers, model.ErrorResponse as XML when the
+// client prefers it and as JSON otherwise. It is respond.Error, re-exported for callers of the handlers.
+func WriteError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	respond.Error(w, r, status, message)
+}
+
+// Writes an error body with the given status, as XML when the client prefers it and as JSON otherwise
+func writeError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	respond.Error(w, r, status, message)
+}
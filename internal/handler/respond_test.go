@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/dodskygge/go_swift/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Unit test for GetSwiftCodeHandler rendering XML
+func TestGetSwiftCodeHandlerXML(t *testing.T) {
+	mockService := new(MockSwiftCodeService)
+	SwiftService = service.NewSwiftCodeService(mockService)
+
+	mockService.On("GetBySwiftCode", mock.Anything, "TESTUS33XXX").Return(&model.SwiftEntity{
+		Address:       "123 Main St",
+		BankName:      "Test Bank",
+		CountryISO2:   "US",
+		CountryName:   "United States",
+		IsHeadquarter: true,
+		SwiftCode:     "TESTUS33XXX",
+	}, nil)
+	mockService.On("GetBranchesByHqSwiftCode", mock.Anything, "TESTUS33").Return([]*model.SwiftEntity{
+		{Address: "456 Branch St", BankName: "Test Bank Branch", CountryISO2: "US", SwiftCode: "TESTUS33ABC"},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/TESTUS33XXX", nil)
+	req.Header.Set("Accept", "application/xml")
+	rec := httptest.NewRecorder()
+	GetSwiftCodeHandler(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/xml", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+	assert.True(t, strings.HasPrefix(rec.Body.String(), xml.Header+"<bank><address>123 Main St</address>"))
+	assert.Contains(t, rec.Body.String(), "<branches><branch><address>456 Branch St</address>")
+
+	var response model.SwiftCodeResponse
+	assert.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "TESTUS33XXX", response.SwiftCode)
+	assert.True(t, response.IsHeadquarter)
+	assert.Len(t, response.Branches, 1)
+
+	// Each representation has its own entity tag
+	xmlETag := rec.Header().Get("ETag")
+	req.Header.Set("Accept", "application/json")
+	rec = httptest.NewRecorder()
+	GetSwiftCodeHandler(rec, req)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.NotEqual(t, xmlETag, rec.Header().Get("ETag"))
+
+	mockService.AssertExpectations(t)
+}
+
+// Unit test for GetSwiftCodesByCountryHandler rendering XML
+func TestGetSwiftCodesByCountryHandlerXML(t *testing.T) {
+	mockService := new(MockSwiftCodeService)
+	SwiftService = service.NewSwiftCodeService(mockService)
+
+	mockService.On("GetByCountry", mock.Anything, "US").Return([]*model.SwiftEntity{
+		{Address: "123 Main St", BankName: "Test Bank", CountryISO2: "US", CountryName: "United States", IsHeadquarter: true, SwiftCode: "TESTUS33XXX"},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/country/US", nil)
+	req.Header.Set("Accept", "text/xml")
+	rec := httptest.NewRecorder()
+	GetSwiftCodesByCountryHandler(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/xml", rec.Header().Get("Content-Type"))
+
+	var response model.SwiftCodesByCountryResponse
+	assert.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "UNITED STATES", response.CountryName)
+	assert.Len(t, response.SwiftCodes, 1)
+	assert.Equal(t, "TESTUS33XXX", response.SwiftCodes[0].SwiftCode)
+
+	mockService.AssertExpectations(t)
+}
+
+// Unit test for GetSwiftCodesByCountryHandler rendering a country without SWIFT codes
+func TestGetSwiftCodesByCountryHandlerEmpty(t *testing.T) {
+	mockService := new(MockSwiftCodeService)
+	SwiftService = service.NewSwiftCodeService(mockService)
+
+	mockService.On("GetByCountry", mock.Anything, "ZZ").Return([]*model.SwiftEntity{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/country/zz", nil)
+	req.Header.Set("Accept", "application/xml")
+	rec := httptest.NewRecorder()
+	GetSwiftCodesByCountryHandler(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response model.SwiftCodesByCountryResponse
+	assert.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "ZZ", response.CountryISO2)
+	assert.Empty(t, response.SwiftCodes)
+
+	req.Header.Set("Accept", "application/json")
+	rec = httptest.NewRecorder()
+	GetSwiftCodesByCountryHandler(rec, req)
+	assert.Contains(t, rec.Body.String(), `"swiftCodes":[]`)
+
+	mockService.AssertExpectations(t)
+}
+
+// Unit test for error bodies and 406 Not Acceptable
+func TestWriteError(t *testing.T) {
+	mockService := new(MockSwiftCodeService)
+	SwiftService = service.NewSwiftCodeService(mockService)
+
+	mockService.On("GetBySwiftCode", mock.Anything, "MISSUS33XXX").Return(nil, model.ErrNotFound)
+
+	// Errors follow the Accept header
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/MISSUS33XXX", nil)
+	req.Header.Set("Accept", "application/xml")
+	rec := httptest.NewRecorder()
+	GetSwiftCodeHandler(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, xml.Header+"<error><message>SWIFT code not found</message></error>\n", rec.Body.String())
+
+	req.Header.Del("Accept")
+	rec = httptest.NewRecorder()
+	GetSwiftCodeHandler(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var response model.ErrorResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "SWIFT code not found", response.Message)
+
+	// Unsupported types are refused before anything is written
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes", strings.NewReader(`{}`))
+	req.Header.Set("Accept", "text/html")
+	rec = httptest.NewRecorder()
+	CreateSwiftCodeHandler(rec, req)
+	assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	mockService.AssertExpectations(t)
+}
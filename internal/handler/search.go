@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dodskygge/go_swift/internal/model"
+)
+
+// Handles GET /api/v1/swift-codes/search?q={query}&country={countryISO2code}&hq=true|false&after={code}&limit={n},
+// listing one page of the SWIFT codes whose code starts with q or whose bank name contains it, ordered by code
+func SearchSwiftCodesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	search := model.SwiftCodeSearch{
+		Query:  query.Get("q"),
+		Filter: model.SwiftCodeFilter{CountryISO2: query.Get("country")},
+		After:  query.Get("after"),
+	}
+	if hq := query.Get("hq"); hq != "" {
+		isHeadquarter, err := strconv.ParseBool(hq)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "Invalid hq filter, expected true or false")
+			return
+		}
+		search.Filter.IsHeadquarter = &isHeadquarter
+	}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			writeError(w, r, http.StatusBadRequest, "Invalid limit, expected a positive number")
+			return
+		}
+		search.Limit = n
+	}
+
+	results, next, err := SwiftService.SearchSwiftCodes(r.Context(), search)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidInput) {
+			writeError(w, r, http.StatusBadRequest, err.Error())
+		} else {
+			writeError(w, r, http.StatusInternalServerError, "Error searching SWIFT codes")
+		}
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, model.SwiftCodeSearchResponse{SwiftCodes: results, Next: next})
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/dodskygge/go_swift/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Unit test for SearchSwiftCodesHandler
+func TestSearchSwiftCodesHandler(t *testing.T) {
+	mockService := new(MockSwiftCodeService)
+	SwiftService = service.NewSwiftCodeService(mockService)
+
+	hq := true
+	mockService.On("Search", mock.Anything, model.SwiftCodeSearch{
+		Query: "test", Filter: model.SwiftCodeFilter{CountryISO2: "US", IsHeadquarter: &hq}, After: "TESTUS33AAA", Limit: 2,
+	}).Return([]*model.SwiftEntity{
+		{SwiftCode: "TESTUS33XXX", BankName: "Test Bank", Address: "123 Main St", CountryISO2: "US", CountryName: "United States", IsHeadquarter: true},
+		{SwiftCode: "TESTUS44XXX", BankName: "Test Bank", Address: "1 Side St", CountryISO2: "US", CountryName: "United States", IsHeadquarter: true},
+	}, nil)
+	mockService.On("Search", mock.Anything, model.SwiftCodeSearch{Limit: service.DefaultSearchLimit + 1}).Return(nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/search?q=test&country=us&hq=true&after=TESTUS33AAA&limit=1", nil)
+	rec := httptest.NewRecorder()
+	SearchSwiftCodesHandler(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"swiftCodes":[{"address":"123 Main St","bankName":"Test Bank","countryISO2":"US","countryName":"UNITED STATES",
+		"isHeadquarter":true,"swiftCode":"TESTUS33XXX"}],"next":"TESTUS33XXX"}`, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/search", nil)
+	rec = httptest.NewRecorder()
+	SearchSwiftCodesHandler(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"swiftCodes":[]}`, rec.Body.String())
+
+	for _, query := range []string{"hq=maybe", "limit=0"} {
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/search?"+query, nil)
+		rec = httptest.NewRecorder()
+		SearchSwiftCodesHandler(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Unit test for New with the JSON format and a request ID in the context
+func TestNewJSONWithRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "json", slog.LevelInfo)
+	assert.NoError(t, err)
+
+	ctx := WithRequestID(context.Background(), "req-123")
+	logger.InfoContext(ctx, "hello", "swift_code", "TESTUS33XXX")
+
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "hello", record["msg"])
+	assert.Equal(t, "req-123", record["request_id"])
+	assert.Equal(t, "TESTUS33XXX", record["swift_code"])
+}
+
+// Unit test for New honoring a runtime level change
+func TestNewLevelVar(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	logger, err := New(&buf, "text", level)
+	assert.NoError(t, err)
+
+	logger.Debug("hidden")
+	assert.Empty(t, buf.String())
+
+	level.Set(slog.LevelDebug)
+	logger.Debug("visible")
+	assert.Contains(t, buf.String(), "visible")
+}
+
+// Unit test for New with an unsupported format
+func TestNewInvalidFormat(t *testing.T) {
+	_, err := New(&bytes.Buffer{}, "xml", slog.LevelInfo)
+	assert.Error(t, err)
+}
+
+// Unit test for ParseLevel
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("warn")
+	assert.NoError(t, err)
+	assert.Equal(t, slog.LevelWarn, level)
+
+	_, err = ParseLevel("verbose")
+	assert.Error(t, err)
+}
@@ -0,0 +1,178 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "go_swift"
+
+// Registry holds every metric exposed on /metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests counts handled HTTP requests per method, route and status.
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Total number of HTTP requests.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPDuration observes HTTP request latency per method, route and status.
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// GRPCRequests counts handled gRPC calls per full method name and status code.
+	GRPCRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_requests_total",
+		Help:      "Total number of gRPC calls.",
+	}, []string{"method", "code"})
+
+	// GRPCDuration observes gRPC call latency per full method name and status code.
+	GRPCDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_request_duration_seconds",
+		Help:      "gRPC call latency in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	// QueryDuration observes repository query latency per method and outcome.
+	QueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "repository_query_duration_seconds",
+		Help:      "Repository query latency in seconds.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"method", "outcome"})
+
+	// CacheRequests counts cache lookups per cache and result ("hit" or "miss").
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Total number of cache lookups by result.",
+	}, []string{"cache", "result"})
+
+	// RateLimited counts requests rejected by the rate limiter per route.
+	RateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_requests_total",
+		Help:      "Total number of requests rejected with 429 by the rate limiter.",
+	}, []string{"route"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		GRPCRequests,
+		GRPCDuration,
+		QueryDuration,
+		CacheRequests,
+		RateLimited,
+	)
+}
+
+// Handler serves the registry in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveQuery records the duration and outcome of a repository query.
+func ObserveQuery(method string, duration time.Duration, err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+	QueryDuration.WithLabelValues(method, outcome).Observe(duration.Seconds())
+}
+
+// ObserveCache records a cache hit or miss.
+func ObserveCache(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	CacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// RegisterDB exposes connection pool statistics from sql.DB.Stats().
+func RegisterDB(db *sql.DB) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, namespace))
+}
+
+// CountryCounter returns the number of SWIFT codes stored per country.
+type CountryCounter interface {
+	CountByCountry(ctx context.Context) (map[string]int, error)
+}
+
+// How long the counts per country are reused by scrapes before being queried again
+const countryCountsTTL = time.Minute
+
+// RegisterCountryCounts exposes a gauge of SWIFT codes per country, queried at most once a minute.
+func RegisterCountryCounts(counter CountryCounter) error {
+	return Registry.Register(&countryCollector{counter: counter, ttl: countryCountsTTL})
+}
+
+var countryCodesDesc = prometheus.NewDesc(
+	namespace+"_swift_codes",
+	"Number of SWIFT codes stored per country.",
+	[]string{"country_iso2"}, nil,
+)
+
+// countryCollector queries the code counts on demand and reuses them for ttl, so that frequent scrapes do
+// not run a GROUP BY over the whole table each.
+type countryCollector struct {
+	counter CountryCounter
+	ttl     time.Duration
+
+	mu        sync.Mutex
+	counts    map[string]int
+	fetchedAt time.Time
+}
+
+func (c *countryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- countryCodesDesc
+}
+
+func (c *countryCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := c.load()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(countryCodesDesc, err)
+		return
+	}
+	for country, count := range counts {
+		ch <- prometheus.MustNewConstMetric(countryCodesDesc, prometheus.GaugeValue, float64(count), country)
+	}
+}
+
+// Returns the cached counts, querying them again once they are older than ttl. Failed queries are not cached.
+func (c *countryCollector) load() (map[string]int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts != nil && time.Since(c.fetchedAt) < c.ttl {
+		return c.counts, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	counts, err := c.counter.CountByCountry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.counts, c.fetchedAt = counts, time.Now()
+	return counts, nil
+}
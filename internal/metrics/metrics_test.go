@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubCountryCounter struct {
+	counts  map[string]int
+	err     error
+	queries *int
+}
+
+func (s stubCountryCounter) CountByCountry(ctx context.Context) (map[string]int, error) {
+	if s.queries != nil {
+		*s.queries++
+	}
+	return s.counts, s.err
+}
+
+// Unit test for the per-country gauge collector
+func TestCountryCollector(t *testing.T) {
+	queries := 0
+	collector := &countryCollector{counter: stubCountryCounter{counts: map[string]int{"PL": 3, "US": 1}, queries: &queries}, ttl: time.Minute}
+
+	expected := `
+# HELP go_swift_swift_codes Number of SWIFT codes stored per country.
+# TYPE go_swift_swift_codes gauge
+go_swift_swift_codes{country_iso2="PL"} 3
+go_swift_swift_codes{country_iso2="US"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+
+	// Scrapes within the TTL reuse the counts
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+	assert.Equal(t, 1, queries)
+
+	collector.fetchedAt = time.Now().Add(-2 * time.Minute)
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+	assert.Equal(t, 2, queries)
+}
+
+// Unit test for the per-country gauge collector when the query fails
+func TestCountryCollectorError(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(&countryCollector{counter: stubCountryCounter{err: errors.New("db down")}})
+
+	_, err := registry.Gather()
+	assert.Error(t, err)
+}
+
+// Unit test for ObserveQuery and ObserveCache
+func TestObserveHelpers(t *testing.T) {
+	ObserveQuery("TestMethod", 10*time.Millisecond, nil)
+	ObserveQuery("TestMethod", 10*time.Millisecond, errors.New("failed"))
+	assert.Equal(t, 1, testutil.CollectAndCount(QueryDuration.WithLabelValues("TestMethod", "ok").(prometheus.Histogram)))
+	assert.Equal(t, 1, testutil.CollectAndCount(QueryDuration.WithLabelValues("TestMethod", "error").(prometheus.Histogram)))
+
+	before := testutil.ToFloat64(CacheRequests.WithLabelValues("test", "hit"))
+	ObserveCache("test", true)
+	assert.Equal(t, before+1, testutil.ToFloat64(CacheRequests.WithLabelValues("test", "hit")))
+}
+
+// Unit test for Handler exposing the text format
+func TestHandler(t *testing.T) {
+	HTTPRequests.WithLabelValues("GET", "/api/v1/health", "200").Inc()
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `go_swift_http_requests_total{method="GET",route="/api/v1/health",status="200"}`)
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dodskygge/go_swift/internal/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+// Unit test for AccessLog recording route, status and bytes with the request ID
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "json", slog.LevelInfo)
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/swift-codes/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("SWIFT code not found"))
+	})
+
+	h := Chain(mux, RequestID, AccessLog(logger, MuxRoute(mux)))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/TESTUS33XXX", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "request completed", record["msg"])
+	assert.Equal(t, "GET", record["method"])
+	assert.Equal(t, "/api/v1/swift-codes/", record["route"])
+	assert.Equal(t, "/api/v1/swift-codes/TESTUS33XXX", record["path"])
+	assert.Equal(t, float64(http.StatusNotFound), record["status"])
+	assert.Equal(t, float64(len("SWIFT code not found")), record["bytes"])
+	assert.Equal(t, "req-1", record["request_id"])
+}
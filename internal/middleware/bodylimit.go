@@ -0,0 +1,17 @@
+package middleware
+
+import "net/http"
+
+// MaxBodySize limits the request bodies of the route patterns to the given number of bytes. Reading past the
+// limit fails with an *http.MaxBytesError, which stops middleware and handlers from buffering larger bodies
+// and lets them answer 413 Request Entity Too Large.
+func MaxBodySize(route RouteFunc, limits map[string]int64) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if limit, ok := limits[route(r)]; ok {
+				r.Body = http.MaxBytesReader(w, r.Body, limit)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Unit test for MaxBodySize
+func TestMaxBodySize(t *testing.T) {
+	route := func(r *http.Request) string { return r.URL.Path }
+	var readErr error
+	h := MaxBodySize(route, map[string]int64{"/api/v1/swift-codes/batch-lookup": 8})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, readErr = io.ReadAll(r.Body)
+		}),
+	)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes/batch-lookup", strings.NewReader("12345678")))
+	assert.NoError(t, readErr)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes/batch-lookup", strings.NewReader("123456789")))
+	var maxBytesErr *http.MaxBytesError
+	assert.True(t, errors.As(readErr, &maxBytesErr))
+
+	// Routes without a limit are left alone
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes", strings.NewReader("123456789")))
+	assert.NoError(t, readErr)
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import "net/http"
+
+// CacheControl sets the Cache-Control header of GET and HEAD responses to the policy configured
+// for the route pattern, e.g. "private, no-cache" to make clients revalidate with ETags.
+func CacheControl(route RouteFunc, policies map[string]string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				if policy := policies[route(r)]; policy != "" {
+					w.Header().Set("Cache-Control", policy)
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
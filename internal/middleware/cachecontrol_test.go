@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Unit test for CacheControl
+func TestCacheControl(t *testing.T) {
+	route := func(r *http.Request) string { return r.URL.Path }
+	h := CacheControl(route, map[string]string{"/api/v1/swift-codes/": "private, max-age=60"})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/", nil))
+	assert.Equal(t, "private, max-age=60", rec.Header().Get("Cache-Control"))
+
+	// Mutations and routes without a policy are left alone
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/swift-codes/", nil))
+	assert.Empty(t, rec.Header().Get("Cache-Control"))
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/health", nil))
+	assert.Empty(t, rec.Header().Get("Cache-Control"))
+}
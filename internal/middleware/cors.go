@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSOptions configures cross-origin access for browser clients.
+type CORSOptions struct {
+	// AllowedOrigins lists exact origins, "*" for any origin, or wildcard subdomains such as "https://*.example.com".
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	MaxAge           time.Duration
+	AllowCredentials bool
+}
+
+// CORS answers preflight requests and adds CORS headers to responses for allowed origins.
+// Preflight requests are answered before authentication since browsers send them without credentials.
+func CORS(opts CORSOptions) Middleware {
+	methods := make([]string, len(opts.AllowedMethods))
+	for i, m := range opts.AllowedMethods {
+		methods[i] = strings.ToUpper(m)
+	}
+	headers := make([]string, len(opts.AllowedHeaders))
+	for i, h := range opts.AllowedHeaders {
+		headers[i] = http.CanonicalHeaderKey(h)
+	}
+	anyOrigin := slices.Contains(opts.AllowedOrigins, "*")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			h := w.Header()
+			h.Add("Vary", "Origin")
+
+			if origin == "" || !originAllowed(opts.AllowedOrigins, origin) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// With credentials the origin must be echoed, browsers reject "*"
+			if anyOrigin && !opts.AllowCredentials {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+			}
+			if opts.AllowCredentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if !preflight {
+				if len(opts.ExposedHeaders) > 0 {
+					h.Set("Access-Control-Expose-Headers", strings.Join(opts.ExposedHeaders, ", "))
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+
+			if !slices.Contains(methods, strings.ToUpper(r.Header.Get("Access-Control-Request-Method"))) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			for _, requested := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+				requested = strings.TrimSpace(requested)
+				if requested != "" && !slices.Contains(headers, http.CanonicalHeaderKey(requested)) {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+			}
+
+			h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+			if len(headers) > 0 {
+				h.Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+			}
+			if opts.MaxAge > 0 {
+				h.Set("Access-Control-Max-Age", strconv.Itoa(int(opts.MaxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+// Reports whether the origin matches one of the allowed origins
+func originAllowed(allowed []string, origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		if pattern == "*" || pattern == origin {
+			return true
+		}
+		// "https://*.example.com" matches any subdomain of example.com over https
+		if prefix, suffix, ok := strings.Cut(pattern, "*"); ok &&
+			strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) &&
+			len(origin) > len(prefix)+len(suffix) && strings.HasPrefix(suffix, ".") {
+			return true
+		}
+	}
+	return false
+}
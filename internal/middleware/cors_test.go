@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func corsTestHandler(opts CORSOptions) http.Handler {
+	return CORS(opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+// Unit test for CORS answering a preflight request
+func TestCORSPreflight(t *testing.T) {
+	h := corsTestHandler(CORSOptions{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedMethods:   []string{"GET", "POST", "DELETE"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		MaxAge:           10 * time.Minute,
+		AllowCredentials: true,
+	})
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/swift-codes/TESTUS33XXX", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "DELETE")
+	req.Header.Set("Access-Control-Request-Headers", "authorization")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "GET, POST, DELETE", rec.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Content-Type, Authorization", rec.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", rec.Header().Get("Access-Control-Max-Age"))
+
+	// A method that is not allowed
+	req.Header.Set("Access-Control-Request-Method", "PATCH")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+// Unit test for CORS on simple requests
+func TestCORSSimpleRequest(t *testing.T) {
+	h := corsTestHandler(CORSOptions{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"GET"},
+		ExposedHeaders: []string{"X-Request-ID"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/country/PL", nil)
+	req.Header.Set("Origin", "https://anywhere.example.org")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "X-Request-ID", rec.Header().Get("Access-Control-Expose-Headers"))
+}
+
+// Unit test for CORS ignoring disallowed origins
+func TestCORSDisallowedOrigin(t *testing.T) {
+	h := corsTestHandler(CORSOptions{
+		AllowedOrigins: []string{"https://*.example.com"},
+		AllowedMethods: []string{"GET"},
+	})
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/health", nil)
+	req.Header.Set("Origin", "https://example.com.evil.net")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+// Unit test for originAllowed
+func TestOriginAllowed(t *testing.T) {
+	allowed := []string{"https://app.example.com", "https://*.partners.example.com"}
+
+	assert.True(t, originAllowed(allowed, "https://app.example.com"))
+	assert.True(t, originAllowed(allowed, "https://bank.partners.example.com"))
+	assert.False(t, originAllowed(allowed, "https://partners.example.com"))
+	assert.False(t, originAllowed(allowed, "http://bank.partners.example.com"))
+	assert.False(t, originAllowed(allowed, "https://other.example.com"))
+}
@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dodskygge/go_swift/internal/auth"
+	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/dodskygge/go_swift/internal/respond"
+)
+
+// IdempotencyKeyHeader carries a client-chosen key identifying a request across retries.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+const (
+	maxIdempotencyKeyLength = 255
+	maxIdempotentBodySize   = 1 << 20
+	// Reservations without a response for longer were left by a crashed process and can be taken over
+	idempotencyLockTimeout = time.Minute
+)
+
+// IdempotencyStore persists idempotency keys and the responses they produced.
+type IdempotencyStore interface {
+	// Reserve stores a new key, or returns the existing record when the key was used before. A reservation
+	// of the same request still without a response and created before staleBefore is taken over instead.
+	Reserve(ctx context.Context, record *model.IdempotencyRecord, staleBefore time.Time) (*model.IdempotencyRecord, error)
+	Complete(ctx context.Context, record *model.IdempotencyRecord) error
+	Release(ctx context.Context, principal, key string) error
+}
+
+// Idempotency makes requests carrying an Idempotency-Key safe to retry. The first request is processed
+// and its response stored; retries with the same key and body get the stored response replayed, retries
+// with a different body get 422 and retries while the first request is still running get 409, unless it
+// has been running for over a minute, which means its process crashed. Keys are scoped to the authenticated
+// principal.
+func Idempotency(store IdempotencyStore) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				respond.Error(w, r, http.StatusBadRequest, "Idempotency-Key is too long")
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodySize+1))
+			if err != nil {
+				respond.Error(w, r, http.StatusBadRequest, "Invalid request data")
+				return
+			}
+			if len(body) > maxIdempotentBodySize {
+				respond.Error(w, r, http.StatusRequestEntityTooLarge, "Request body is too large")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			record := &model.IdempotencyRecord{
+				Key:         key,
+				RequestHash: requestHash(r, body),
+				CreatedAt:   time.Now().UTC(),
+			}
+			if p := auth.PrincipalFromContext(r.Context()); p != nil {
+				record.Principal = p.ID
+			}
+
+			existing, err := store.Reserve(r.Context(), record, record.CreatedAt.Add(-idempotencyLockTimeout))
+			if err != nil {
+				slog.ErrorContext(r.Context(), "failed to reserve idempotency key", "error", err)
+				respond.Error(w, r, http.StatusInternalServerError, "Failed to process idempotency key")
+				return
+			}
+			if existing != nil {
+				replay(w, r, existing, record.RequestHash)
+				return
+			}
+
+			// Server errors and panics are not stored so that the request can be retried with the same key
+			ctx := context.WithoutCancel(r.Context())
+			release := func() {
+				if err := store.Release(ctx, record.Principal, key); err != nil {
+					slog.ErrorContext(ctx, "failed to release idempotency key", "error", err)
+				}
+			}
+
+			rec := &bodyRecorder{responseRecorder: newResponseRecorder(w)}
+			serve(next, rec, r, release)
+
+			if rec.status >= http.StatusInternalServerError {
+				release()
+				return
+			}
+
+			record.StatusCode = rec.status
+			record.ContentType = rec.Header().Get("Content-Type")
+			record.ResponseBody = rec.body.Bytes()
+			if err := store.Complete(ctx, record); err != nil {
+				slog.ErrorContext(ctx, "failed to store idempotent response", "error", err)
+			}
+		})
+	}
+}
+
+// Serves the request, calling release before passing on a panic of the handler
+func serve(next http.Handler, w http.ResponseWriter, r *http.Request, release func()) {
+	defer func() {
+		if err := recover(); err != nil {
+			release()
+			panic(err)
+		}
+	}()
+	next.ServeHTTP(w, r)
+}
+
+// Answers a retried request from the stored record
+func replay(w http.ResponseWriter, r *http.Request, existing *model.IdempotencyRecord, hash string) {
+	if existing.RequestHash != hash {
+		respond.Error(w, r, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+		return
+	}
+	if existing.StatusCode == 0 {
+		w.Header().Set("Retry-After", "1")
+		respond.Error(w, r, http.StatusConflict, "A request with this Idempotency-Key is still being processed")
+		return
+	}
+
+	if existing.ContentType != "" {
+		w.Header().Set("Content-Type", existing.ContentType)
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(existing.StatusCode)
+	w.Write(existing.ResponseBody)
+}
+
+// Hashes the method, path, query and body so a key cannot be reused for a different request
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// bodyRecorder keeps a copy of the response body in addition to the status.
+type bodyRecorder struct {
+	*responseRecorder
+	body bytes.Buffer
+}
+
+func (rec *bodyRecorder) Write(b []byte) (int, error) {
+	rec.body.Write(b)
+	return rec.responseRecorder.Write(b)
+}
@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// In-memory idempotency store for testing
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*model.IdempotencyRecord
+}
+
+func (s *memoryIdempotencyStore) Reserve(ctx context.Context, record *model.IdempotencyRecord, staleBefore time.Time) (*model.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, ok := s.records[record.Principal+"|"+record.Key]
+	stale := ok && existing.StatusCode == 0 && existing.RequestHash == record.RequestHash && existing.CreatedAt.Before(staleBefore)
+	if ok && !stale {
+		copied := *existing
+		return &copied, nil
+	}
+	copied := *record
+	s.records[record.Principal+"|"+record.Key] = &copied
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(ctx context.Context, record *model.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *record
+	s.records[record.Principal+"|"+record.Key] = &copied
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, principal, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, principal+"|"+key)
+	return nil
+}
+
+// Unit test for Idempotency replaying responses
+func TestIdempotency(t *testing.T) {
+	store := &memoryIdempotencyStore{records: make(map[string]*model.IdempotencyRecord)}
+	calls := 0
+	h := Idempotency(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"message":"SWIFT code created successfully"}`))
+	}))
+
+	post := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := post("onboarding-42", `{"swiftCode":"TESTUS33XXX"}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, 1, calls)
+
+	// A retry replays the stored response without calling the handler
+	retry := post("onboarding-42", `{"swiftCode":"TESTUS33XXX"}`)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "application/json", retry.Header().Get("Content-Type"))
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, 1, calls)
+
+	// The same key with a different body
+	assert.Equal(t, http.StatusUnprocessableEntity, post("onboarding-42", `{"swiftCode":"OTHERUS33XXX"}`).Code)
+	assert.Equal(t, 1, calls)
+
+	// The same key and body with a different query
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes?dryRun=true", strings.NewReader(`{"swiftCode":"TESTUS33XXX"}`))
+	req.Header.Set(IdempotencyKeyHeader, "onboarding-42")
+	req.Header.Set("Accept", "application/xml")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "application/xml", rec.Header().Get("Content-Type"))
+	assert.Equal(t, 1, calls)
+
+	// Requests without a key are not deduplicated
+	post("", `{"swiftCode":"TESTUS33XXX"}`)
+	assert.Equal(t, 2, calls)
+
+	// A key whose first request is still running
+	inFlight := &model.IdempotencyRecord{
+		Key:         "in-flight",
+		RequestHash: requestHash(httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes", nil), []byte("{}")),
+		CreatedAt:   time.Now().UTC(),
+	}
+	store.records["|in-flight"] = inFlight
+	rec = post("in-flight", "{}")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	assert.Equal(t, 2, calls)
+
+	// A reservation left behind by a crashed process is taken over after the lock timeout
+	inFlight.CreatedAt = time.Now().UTC().Add(-2 * idempotencyLockTimeout)
+	assert.Equal(t, http.StatusCreated, post("in-flight", "{}").Code)
+	assert.Equal(t, 3, calls)
+}
+
+// Unit test for Idempotency releasing keys after server errors
+func TestIdempotencyServerError(t *testing.T) {
+	store := &memoryIdempotencyStore{records: make(map[string]*model.IdempotencyRecord)}
+	status := http.StatusInternalServerError
+	h := Idempotency(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes", strings.NewReader("{}"))
+	req.Header.Set(IdempotencyKeyHeader, "retry-me")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Empty(t, store.records)
+
+	status = http.StatusCreated
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes", strings.NewReader("{}"))
+	req.Header.Set(IdempotencyKeyHeader, "retry-me")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, http.StatusCreated, store.records["|retry-me"].StatusCode)
+}
+
+// Unit test for Idempotency releasing keys when the handler panics
+func TestIdempotencyPanic(t *testing.T) {
+	store := &memoryIdempotencyStore{records: make(map[string]*model.IdempotencyRecord)}
+	h := Idempotency(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes", strings.NewReader("{}"))
+	req.Header.Set(IdempotencyKeyHeader, "retry-me")
+	assert.PanicsWithValue(t, "boom", func() { h.ServeHTTP(httptest.NewRecorder(), req) })
+	assert.Empty(t, store.records)
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dodskygge/go_swift/internal/metrics"
+)
+
+// Metrics records request counts and latency histograms per method, route and status.
+func Metrics(route RouteFunc) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := newResponseRecorder(w)
+
+			next.ServeHTTP(rec, r)
+
+			pattern := route(r)
+			if pattern == "" {
+				pattern = "unmatched"
+			}
+			status := strconv.Itoa(rec.status)
+			metrics.HTTPRequests.WithLabelValues(r.Method, pattern, status).Inc()
+			metrics.HTTPDuration.WithLabelValues(r.Method, pattern, status).Observe(time.Since(start).Seconds())
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dodskygge/go_swift/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// Unit test for Metrics labelling requests by route pattern
+func TestMetrics(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/swift-codes/country/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	counter := metrics.HTTPRequests.WithLabelValues(http.MethodGet, "/api/v1/swift-codes/country/", "200")
+	before := testutil.ToFloat64(counter)
+
+	h := Chain(mux, Metrics(MuxRoute(mux)))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/country/PL", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/country/US", nil))
+
+	assert.Equal(t, before+2, testutil.ToFloat64(counter))
+}
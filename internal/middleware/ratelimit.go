@@ -0,0 +1,254 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dodskygge/go_swift/internal/auth"
+	"github.com/dodskygge/go_swift/internal/metrics"
+	"github.com/dodskygge/go_swift/internal/respond"
+)
+
+// Limit is a token bucket refilled at RPS tokens per second holding at most Burst tokens.
+// A zero RPS disables limiting.
+type Limit struct {
+	RPS   float64 `json:"rps"`
+	Burst int     `json:"burst"`
+}
+
+// RateLimitOptions configures the rate limiter.
+type RateLimitOptions struct {
+	// Default applies to routes without an entry in Routes.
+	Default Limit
+	// Routes overrides the limit per route pattern.
+	Routes map[string]Limit
+	// TrustForwardedFor keys anonymous clients by the last X-Forwarded-For address, the one appended by the
+	// proxy in front of the server; only enable it behind a proxy that appends to the header.
+	TrustForwardedFor bool
+}
+
+const bucketIdleTimeout = 10 * time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimiter keeps a token bucket per client and route.
+type RateLimiter struct {
+	opts RateLimitOptions
+	now  func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewRateLimiter creates a rate limiter with the given options.
+func NewRateLimiter(opts RateLimitOptions) *RateLimiter {
+	return &RateLimiter{opts: opts, now: time.Now, buckets: make(map[string]*bucket)}
+}
+
+// Middleware rejects requests exceeding the limit with 429 and reports the quota in RateLimit-* headers.
+// Authenticated callers are limited per principal, anonymous ones per client IP.
+func (rl *RateLimiter) Middleware(route RouteFunc) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pattern := route(r)
+			limit := rl.limit(pattern)
+			if limit.RPS <= 0 || pattern == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			allowed, remaining, retryAfter, reset := rl.take(rl.clientKey(r)+"|"+pattern, limit)
+
+			h := w.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+			h.Set("RateLimit-Remaining", strconv.Itoa(remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(reset)))
+			h.Set("RateLimit-Policy", strconv.Itoa(limit.Burst)+";w="+strconv.Itoa(ceilSeconds(time.Duration(float64(limit.Burst)/limit.RPS*float64(time.Second)))))
+
+			if !allowed {
+				metrics.RateLimited.WithLabelValues(pattern).Inc()
+				h.Set("Retry-After", strconv.Itoa(ceilSeconds(retryAfter)))
+				respond.Error(w, r, http.StatusTooManyRequests, "Too many requests")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Allow takes a token for the request to route, returning whether it is allowed and otherwise the wait
+// until the next token, for callers other than HTTP handlers such as gRPC interceptors.
+func (rl *RateLimiter) Allow(r *http.Request, route string) (bool, time.Duration) {
+	limit := rl.limit(route)
+	if limit.RPS <= 0 || route == "" {
+		return true, 0
+	}
+
+	allowed, _, retryAfter, _ := rl.take(rl.clientKey(r)+"|"+route, limit)
+	if !allowed {
+		metrics.RateLimited.WithLabelValues(route).Inc()
+	}
+	return allowed, retryAfter
+}
+
+// Route of the buckets counting failed authentication attempts, shared by every route of a client
+const authFailuresRoute = "auth-failures"
+
+// AuthFailures limits failed authentication attempts per client IP to the default limit, so that API keys and
+// tokens cannot be guessed faster than the API may be called. It runs before authentication: clients out of
+// attempts get 429 and every 401 or 403 response takes an attempt.
+func (rl *RateLimiter) AuthFailures(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if allowed, retryAfter := rl.AllowAuthAttempt(r); !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(retryAfter)))
+			respond.Error(w, r, http.StatusTooManyRequests, "Too many requests")
+			return
+		}
+
+		rec := newResponseRecorder(w)
+		next.ServeHTTP(rec, r)
+
+		if rec.status == http.StatusUnauthorized || rec.status == http.StatusForbidden {
+			rl.RecordAuthFailure(r)
+		}
+	})
+}
+
+// AllowAuthAttempt reports whether the client of r has failed authentication attempts left, and otherwise the
+// wait until it has, without taking one.
+func (rl *RateLimiter) AllowAuthAttempt(r *http.Request) (bool, time.Duration) {
+	limit := rl.opts.Default
+	if limit.RPS <= 0 {
+		return true, 0
+	}
+
+	allowed, retryAfter := rl.peek(rl.addressKey(r)+"|"+authFailuresRoute, limit)
+	if !allowed {
+		metrics.RateLimited.WithLabelValues(authFailuresRoute).Inc()
+	}
+	return allowed, retryAfter
+}
+
+// RecordAuthFailure takes a failed authentication attempt of the client of r.
+func (rl *RateLimiter) RecordAuthFailure(r *http.Request) {
+	if limit := rl.opts.Default; limit.RPS > 0 {
+		rl.take(rl.addressKey(r)+"|"+authFailuresRoute, limit)
+	}
+}
+
+// Returns the limit of a route
+func (rl *RateLimiter) limit(route string) Limit {
+	if limit, ok := rl.opts.Routes[route]; ok {
+		return limit
+	}
+	return rl.opts.Default
+}
+
+// Takes a token from the bucket, returning whether it was allowed, the tokens left,
+// the wait until the next token and the time until the bucket is full again
+func (rl *RateLimiter) take(key string, limit Limit) (bool, int, time.Duration, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	b := rl.refill(key, limit)
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	var retryAfter time.Duration
+	if !allowed {
+		retryAfter = secondsToDuration((1 - b.tokens) / limit.RPS)
+	}
+	reset := secondsToDuration((float64(limit.Burst) - b.tokens) / limit.RPS)
+
+	return allowed, int(b.tokens), retryAfter, reset
+}
+
+// Reports whether the bucket has a token left and otherwise the wait until the next one, without taking it
+func (rl *RateLimiter) peek(key string, limit Limit) (bool, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	b := rl.refill(key, limit)
+	if b.tokens >= 1 {
+		return true, 0
+	}
+	return false, secondsToDuration((1 - b.tokens) / limit.RPS)
+}
+
+// Returns the bucket of key refilled for the time elapsed since the last request; the caller holds the lock
+func (rl *RateLimiter) refill(key string, limit Limit) *bucket {
+	now := rl.now()
+	rl.sweep(now)
+
+	b, ok := rl.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		rl.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.RPS)
+	b.last = now
+	return b
+}
+
+// Drops idle buckets at most once per idle timeout; the caller holds the lock
+func (rl *RateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < bucketIdleTimeout {
+		return
+	}
+	for key, b := range rl.buckets {
+		if now.Sub(b.last) > bucketIdleTimeout {
+			delete(rl.buckets, key)
+		}
+	}
+	rl.lastSweep = now
+}
+
+// Identifies the client by authenticated principal or IP address
+func (rl *RateLimiter) clientKey(r *http.Request) string {
+	if p := auth.PrincipalFromContext(r.Context()); p != nil {
+		return p.ID
+	}
+	return rl.addressKey(r)
+}
+
+// Identifies the client by IP address
+func (rl *RateLimiter) addressKey(r *http.Request) string {
+	// Earlier entries are set by the client and cannot be trusted
+	if rl.opts.TrustForwardedFor {
+		if values := r.Header.Values("X-Forwarded-For"); len(values) > 0 {
+			forwarded := values[len(values)-1]
+			if last := strings.TrimSpace(forwarded[strings.LastIndex(forwarded, ",")+1:]); last != "" {
+				return "ip:" + last
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// Rounds a duration up to whole seconds
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dodskygge/go_swift/internal/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+// Unit test for RateLimiter enforcing bursts and refilling tokens
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2025, 4, 23, 12, 0, 0, 0, time.UTC)
+	rl := NewRateLimiter(RateLimitOptions{
+		Default: Limit{RPS: 1, Burst: 2},
+		Routes: map[string]Limit{
+			"/api/v1/health": {},
+		},
+	})
+	rl.now = func() time.Time { return now }
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/swift-codes/", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/api/v1/health", func(w http.ResponseWriter, r *http.Request) {})
+	h := Chain(mux, rl.Middleware(MuxRoute(mux)))
+
+	do := func(path, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The burst is available immediately
+	rec := do("/api/v1/swift-codes/TESTUS33XXX", "10.0.0.1:5000")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, http.StatusOK, do("/api/v1/swift-codes/TESTUS33XXX", "10.0.0.1:5001").Code)
+
+	// The bucket is empty
+	rec = do("/api/v1/swift-codes/TESTUS33XXX", "10.0.0.1:5002")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2", rec.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	// Other clients and unlimited routes are not affected
+	assert.Equal(t, http.StatusOK, do("/api/v1/swift-codes/TESTUS33XXX", "10.0.0.2:5000").Code)
+	rec = do("/api/v1/health", "10.0.0.1:5003")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+
+	// A token is refilled after one second
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, do("/api/v1/swift-codes/TESTUS33XXX", "10.0.0.1:5004").Code)
+	assert.Equal(t, http.StatusTooManyRequests, do("/api/v1/swift-codes/TESTUS33XXX", "10.0.0.1:5005").Code)
+}
+
+// Unit test for RateLimiter keying authenticated clients by principal
+func TestRateLimiterPrincipalKey(t *testing.T) {
+	rl := NewRateLimiter(RateLimitOptions{Default: Limit{RPS: 1, Burst: 1}})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.Equal(t, "ip:192.0.2.1", rl.clientKey(req))
+
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{ID: "apikey:7"}))
+	assert.Equal(t, "apikey:7", rl.clientKey(req))
+
+	rl = NewRateLimiter(RateLimitOptions{TrustForwardedFor: true})
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Forwarded-For", "203.0.113.5, 198.51.100.7")
+	assert.Equal(t, "ip:198.51.100.7", rl.clientKey(req))
+
+	// Only the entry appended by the proxy counts, whatever the client sent
+	req.Header.Set("X-Forwarded-For", "203.0.113.6")
+	req.Header.Add("X-Forwarded-For", "198.51.100.7")
+	assert.Equal(t, "ip:198.51.100.7", rl.clientKey(req))
+}
+
+// Unit test for RateLimiter limiting failed authentication attempts per client IP
+func TestRateLimiterAuthFailures(t *testing.T) {
+	now := time.Date(2025, 4, 23, 12, 0, 0, 0, time.UTC)
+	rl := NewRateLimiter(RateLimitOptions{Default: Limit{RPS: 1, Burst: 2}})
+	rl.now = func() time.Time { return now }
+
+	h := rl.AuthFailures(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "valid" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	do := func(key, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/TESTUS33XXX", nil)
+		req.Header.Set("X-API-Key", key)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Successful requests do not count
+	for range 3 {
+		assert.Equal(t, http.StatusOK, do("valid", "10.0.0.1:5000").Code)
+	}
+
+	// Guesses use up the attempts of the address, whatever key is tried
+	assert.Equal(t, http.StatusUnauthorized, do("guess-1", "10.0.0.1:5000").Code)
+	assert.Equal(t, http.StatusUnauthorized, do("guess-2", "10.0.0.1:5001").Code)
+	rec := do("guess-3", "10.0.0.1:5002")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusTooManyRequests, do("valid", "10.0.0.1:5003").Code)
+
+	// Other addresses are not affected, and attempts are refilled over time
+	assert.Equal(t, http.StatusUnauthorized, do("guess-1", "10.0.0.2:5000").Code)
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, do("valid", "10.0.0.1:5004").Code)
+}
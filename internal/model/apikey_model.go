@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// Entity representing an API key in the database, the key itself is never stored
+type APIKeyEntity struct {
+	ID        int64
+	Name      string
+	KeyPrefix string
+	KeyHash   string
+	Scopes    []string
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
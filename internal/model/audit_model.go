@@ -0,0 +1,58 @@
+package model
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Actions recorded in the audit log
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// State of a SWIFT code recorded before or after a change
+type AuditSnapshot struct {
+	Address       string `json:"address" xml:"address"`
+	BankName      string `json:"bankName" xml:"bankName"`
+	CountryISO2   string `json:"countryISO2" xml:"countryISO2"`
+	CountryName   string `json:"countryName" xml:"countryName"`
+	IsHeadquarter bool   `json:"isHeadquarter" xml:"isHeadquarter"`
+	SwiftCode     string `json:"swiftCode" xml:"swiftCode"`
+	Version       int64  `json:"version" xml:"version"`
+}
+
+// Entry of the audit log recording one change of a SWIFT code
+type AuditEntry struct {
+	ID   int64     `json:"id" xml:"id"`
+	Time time.Time `json:"time" xml:"time"`
+	// Actor is the ID of the principal that made the change, "anonymous" when authentication is disabled
+	Actor     string `json:"actor" xml:"actor"`
+	Action    string `json:"action" xml:"action"`
+	SwiftCode string `json:"swiftCode" xml:"swiftCode"`
+	// Before is unset for creations and After for deletions
+	Before    *AuditSnapshot `json:"before,omitempty" xml:"before,omitempty"`
+	After     *AuditSnapshot `json:"after,omitempty" xml:"after,omitempty"`
+	RequestID string         `json:"requestId,omitempty" xml:"requestId,omitempty"`
+}
+
+// Query of the audit log, entries are ordered by ID; zero values match every entry
+type AuditQuery struct {
+	SwiftCode string
+	Actor     string
+	Action    string
+	// Since and Until bound the time of the entries, inclusively
+	Since time.Time
+	Until time.Time
+	// After skips entries up to and including this ID, for paging through results
+	After int64
+	Limit int
+}
+
+// Response listing audit log entries; Next is passed as after to get the next page, 0 on the last page
+type AuditLogResponse struct {
+	XMLName xml.Name     `json:"-" xml:"auditLog"`
+	Entries []AuditEntry `json:"entries" xml:"entry"`
+	Next    int64        `json:"next,omitempty" xml:"next,omitempty"`
+}
@@ -1,39 +1,79 @@
 package model
 
+import (
+	"encoding/xml"
+	"time"
+)
+
 // Response for a single SWIFT code
 type SwiftCodeResponse struct {
-	Address       string            `json:"address"`
-	BankName      string            `json:"bankName"`
-	CountryISO2   string            `json:"countryISO2"`
-	CountryName   string            `json:"countryName"`
-	IsHeadquarter bool              `json:"isHeadquarter"`
-	SwiftCode     string            `json:"swiftCode"`
-	Branches      []SwiftCodeBranch `json:"branches,omitempty"`
+	XMLName       xml.Name          `json:"-" xml:"bank"`
+	Address       string            `json:"address" xml:"address"`
+	BankName      string            `json:"bankName" xml:"bankName"`
+	CountryISO2   string            `json:"countryISO2" xml:"countryISO2"`
+	CountryName   string            `json:"countryName" xml:"countryName"`
+	IsHeadquarter bool              `json:"isHeadquarter" xml:"isHeadquarter"`
+	SwiftCode     string            `json:"swiftCode" xml:"swiftCode"`
+	Branches      []SwiftCodeBranch `json:"branches,omitempty" xml:"branches>branch,omitempty"`
+	// LastModified is the latest update of the code itself, sent as the Last-Modified header of branches.
+	// Headquarters responses list branches whose deletion leaves no date behind, so they rely on the ETag.
+	LastModified time.Time `json:"-" xml:"-"`
+	// Version of the code, part of the ETag and expected back in If-Match
+	Version int64 `json:"-" xml:"-"`
+	// ETag the code was received with by clients, to be sent back as is in If-Match
+	ETag string `json:"-" xml:"-"`
 }
 
 // Response for a branch of a SWIFT code
 type SwiftCodeBranch struct {
-	Address       string `json:"address"`
-	BankName      string `json:"bankName"`
-	CountryISO2   string `json:"countryISO2"`
-	IsHeadquarter bool   `json:"isHeadquarter"`
-	SwiftCode     string `json:"swiftCode"`
+	Address       string `json:"address" xml:"address"`
+	BankName      string `json:"bankName" xml:"bankName"`
+	CountryISO2   string `json:"countryISO2" xml:"countryISO2"`
+	IsHeadquarter bool   `json:"isHeadquarter" xml:"isHeadquarter"`
+	SwiftCode     string `json:"swiftCode" xml:"swiftCode"`
 }
 
 // Response for SWIFT codes by country
 type SwiftCodesByCountryResponse struct {
-	CountryISO2 string                     `json:"countryISO2"`
-	CountryName string                     `json:"countryName"`
-	SwiftCodes  []SwiftCodeMinimalResponse `json:"swiftCodes"`
+	XMLName     xml.Name                   `json:"-" xml:"country"`
+	CountryISO2 string                     `json:"countryISO2" xml:"countryISO2"`
+	CountryName string                     `json:"countryName" xml:"countryName"`
+	SwiftCodes  []SwiftCodeMinimalResponse `json:"swiftCodes" xml:"swiftCodes>bank"`
 }
 
 // Minimal response for a SWIFT code
 type SwiftCodeMinimalResponse struct {
-	Address       string `json:"address"`
-	BankName      string `json:"bankName"`
-	CountryISO2   string `json:"countryISO2"`
-	IsHeadquarter bool   `json:"isHeadquarter"`
-	SwiftCode     string `json:"swiftCode"`
+	Address       string `json:"address" xml:"address"`
+	BankName      string `json:"bankName" xml:"bankName"`
+	CountryISO2   string `json:"countryISO2" xml:"countryISO2"`
+	IsHeadquarter bool   `json:"isHeadquarter" xml:"isHeadquarter"`
+	SwiftCode     string `json:"swiftCode" xml:"swiftCode"`
+}
+
+// Response for the SWIFT codes sharing an institution code, grouped by country
+type InstitutionResponse struct {
+	XMLName         xml.Name             `json:"-" xml:"institution"`
+	InstitutionCode string               `json:"institutionCode" xml:"institutionCode"`
+	Countries       []InstitutionCountry `json:"countries" xml:"countries>country"`
+}
+
+// SWIFT codes of an institution in one country
+type InstitutionCountry struct {
+	CountryISO2 string                     `json:"countryISO2" xml:"countryISO2"`
+	CountryName string                     `json:"countryName" xml:"countryName"`
+	SwiftCodes  []SwiftCodeMinimalResponse `json:"swiftCodes" xml:"swiftCodes>bank"`
+}
+
+// Response confirming a change
+type MessageResponse struct {
+	XMLName xml.Name `json:"-" xml:"response"`
+	Message string   `json:"message" xml:"message"`
+}
+
+// Response body of a failed request
+type ErrorResponse struct {
+	XMLName xml.Name `json:"-" xml:"error"`
+	Message string   `json:"error" xml:"message"`
 }
 
 // Request to create a new SWIFT code
@@ -46,6 +86,118 @@ type CreateSwiftCodeRequest struct {
 	SwiftCode     string `json:"swiftCode"`
 }
 
+// Request to update an existing SWIFT code; the code itself and its headquarters status cannot change
+type UpdateSwiftCodeRequest struct {
+	Address     string `json:"address"`
+	BankName    string `json:"bankName"`
+	CountryISO2 string `json:"countryISO2"`
+	CountryName string `json:"countryName"`
+}
+
+// Request to look up many SWIFT codes at once
+type BatchLookupRequest struct {
+	SwiftCodes []string `json:"swiftCodes"`
+}
+
+// Response for a batch lookup; found codes are listed in request order without branches
+type BatchLookupResponse struct {
+	XMLName  xml.Name            `json:"-" xml:"batchLookup"`
+	Found    []SwiftCodeResponse `json:"found" xml:"found>bank"`
+	NotFound []string            `json:"notFound" xml:"notFound>swiftCode"`
+	Invalid  []string            `json:"invalid" xml:"invalid>swiftCode"`
+}
+
+// Bulk write modes
+const (
+	// BulkAllOrNothing applies no record unless every record succeeds
+	BulkAllOrNothing = "all-or-nothing"
+	// BulkBestEffort applies every valid record and reports the failed ones
+	BulkBestEffort = "best-effort"
+)
+
+// Outcomes of a record in a bulk write
+const (
+	BulkStatusCreated = "created"
+	BulkStatusUpdated = "updated"
+	BulkStatusFailed  = "failed"
+	// BulkStatusNotApplied marks records discarded because another record failed in all-or-nothing mode
+	BulkStatusNotApplied = "not_applied"
+)
+
+// Outcome of a single record in a bulk write, Index is the position of the record in the request
+type BulkItemResult struct {
+	Index     int    `json:"index" xml:"index"`
+	SwiftCode string `json:"swiftCode" xml:"swiftCode"`
+	Status    string `json:"status" xml:"status"`
+	Error     string `json:"error,omitempty" xml:"error,omitempty"`
+}
+
+// Response for a bulk write
+type BulkWriteResponse struct {
+	XMLName   xml.Name         `json:"-" xml:"bulkWrite"`
+	Mode      string           `json:"mode" xml:"mode"`
+	Committed bool             `json:"committed" xml:"committed"`
+	Created   int              `json:"created" xml:"created"`
+	Updated   int              `json:"updated" xml:"updated"`
+	Failed    int              `json:"failed" xml:"failed"`
+	Items     []BulkItemResult `json:"items" xml:"items>item"`
+}
+
+// Filter for listing SWIFT codes; zero values match every code
+type SwiftCodeFilter struct {
+	CountryISO2   string
+	IsHeadquarter *bool
+}
+
+// Search for SWIFT codes, results are ordered by code
+type SwiftCodeSearch struct {
+	// Query matches a prefix of the SWIFT code or part of the bank name, case-insensitively
+	Query  string
+	Filter SwiftCodeFilter
+	// After skips codes up to and including it, for paging through results
+	After string
+	Limit int
+}
+
+// Response listing one page of search results; Next is passed as after to get the next page, empty on the last page
+type SwiftCodeSearchResponse struct {
+	XMLName    xml.Name            `json:"-" xml:"search"`
+	SwiftCodes []SwiftCodeResponse `json:"swiftCodes" xml:"swiftCodes>bank"`
+	Next       string              `json:"next,omitempty" xml:"next,omitempty"`
+}
+
+// Country of the ISO 3166 reference table with the number of its SWIFT codes
+type Country struct {
+	ISO2           string `json:"iso2" xml:"iso2"`
+	ISO3           string `json:"iso3" xml:"iso3"`
+	Numeric        string `json:"numeric" xml:"numeric"`
+	Name           string `json:"name" xml:"name"`
+	SwiftCodeCount int    `json:"swiftCodeCount" xml:"swiftCodeCount"`
+}
+
+// Response listing the reference countries
+type CountriesResponse struct {
+	XMLName   xml.Name  `json:"-" xml:"countries"`
+	Countries []Country `json:"countries" xml:"country"`
+}
+
+// Statistics of the SWIFT codes of all countries, or of one country when CountryISO2 is set
+type SwiftCodeStats struct {
+	XMLName     xml.Name `json:"-" xml:"stats"`
+	CountryISO2 string   `json:"countryISO2,omitempty" xml:"countryISO2,omitempty"`
+	CountryName string   `json:"countryName,omitempty" xml:"countryName,omitempty"`
+	// Countries is the number of countries with SWIFT codes, only set for all countries
+	Countries    int `json:"countries,omitempty" xml:"countries,omitempty"`
+	SwiftCodes   int `json:"swiftCodes" xml:"swiftCodes"`
+	Headquarters int `json:"headquarters" xml:"headquarters"`
+	Branches     int `json:"branches" xml:"branches"`
+	// Institutions is the number of distinct institution codes, the first 4 characters of the SWIFT codes
+	Institutions int `json:"institutions" xml:"institutions"`
+	Towns        int `json:"towns" xml:"towns"`
+	// LastUpdated is the latest change of any of the SWIFT codes, unset when there are none
+	LastUpdated *time.Time `json:"lastUpdated,omitempty" xml:"lastUpdated,omitempty"`
+}
+
 // Entity representing a SWIFT code in the database
 type SwiftEntity struct {
 	Address       string
@@ -54,4 +206,6 @@ type SwiftEntity struct {
 	CountryName   string
 	IsHeadquarter bool
 	SwiftCode     string
+	UpdatedAt     time.Time
+	Version       int64
 }
@@ -0,0 +1,14 @@
+package model
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when a SWIFT code does not exist.
+	ErrNotFound = errors.New("no SWIFT code found with the given value")
+	// ErrAlreadyExists is returned when creating a SWIFT code that is already stored.
+	ErrAlreadyExists = errors.New("SWIFT code already exists")
+	// ErrVersionMismatch is returned when a SWIFT code changed since the version a client based its change on.
+	ErrVersionMismatch = errors.New("SWIFT code was modified by another request")
+	// ErrInvalidInput is matched by errors for SWIFT code data that fails validation.
+	ErrInvalidInput = errors.New("invalid SWIFT code data")
+)
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// Entity representing a request made with an Idempotency-Key and the response it produced
+type IdempotencyRecord struct {
+	// Principal scopes the key to the caller that used it, empty for anonymous callers
+	Principal   string
+	Key         string
+	RequestHash string
+	// StatusCode is 0 while the original request is still being processed
+	StatusCode   int
+	ContentType  string
+	ResponseBody []byte
+	CreatedAt    time.Time
+}
@@ -0,0 +1,178 @@
+// Package openapi holds the OpenAPI document describing the HTTP API, the handlers serving it and
+// a middleware validating requests against it.
+package openapi
+
+import (
+	"bufio"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"github.com/dodskygge/go_swift/internal/handler"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+)
+
+//go:embed openapi.yaml
+var specYAML []byte
+
+// Swagger UI, vendored so that the docs work offline and load no third-party code
+//
+//go:embed swagger-ui/index.html swagger-ui/swagger-initializer.js swagger-ui/swagger-ui-bundle.js swagger-ui/swagger-ui.css
+var swaggerUI embed.FS
+
+// Path the docs are served under
+const docsPrefix = "/api/v1/docs/"
+
+// Media types of NDJSON request bodies, decoded as an array of their lines
+var ndjsonTypes = []string{"application/x-ndjson", "application/ndjson", "application/jsonl"}
+
+func init() {
+	for _, contentType := range ndjsonTypes {
+		openapi3filter.RegisterBodyDecoder(contentType, ndjsonBodyDecoder)
+	}
+}
+
+// Load parses the embedded OpenAPI document and checks that it is valid.
+func Load() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(specYAML)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	return doc, nil
+}
+
+// Handler serves the document as JSON.
+func Handler(doc *openapi3.T) (http.Handler, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			handler.WriteError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}), nil
+}
+
+// DocsHandler serves Swagger UI under /api/v1/docs/, rendering the document from /api/v1/openapi.json.
+func DocsHandler() http.Handler {
+	files, err := fs.Sub(swaggerUI, "swagger-ui")
+	if err != nil {
+		panic(err)
+	}
+	fileServer := http.StripPrefix(docsPrefix, http.FileServerFS(files))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			handler.WriteError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		fileServer.ServeHTTP(w, r)
+	})
+}
+
+// Validator returns a middleware answering 400 Bad Request to requests whose parameters or body do not
+// match the document. Requests for paths or methods the document does not describe are passed through so
+// the mux can answer them, and security requirements are left to the auth middleware.
+func Validator(doc *openapi3.T) (func(http.Handler) http.Handler, error) {
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &openapi3filter.Options{
+		AuthenticationFunc:  openapi3filter.NoopAuthenticationFunc,
+		SkipSettingDefaults: true,
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := router.FindRoute(r)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			err = openapi3filter.ValidateRequest(r.Context(), &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			})
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					handler.WriteError(w, r, http.StatusRequestEntityTooLarge, "Request body is too large")
+				} else {
+					handler.WriteError(w, r, http.StatusBadRequest, "Invalid request: "+validationMessage(err))
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// Returns a short description of a validation error, without the schema dump kin-openapi appends
+func validationMessage(err error) string {
+	var reqErr *openapi3filter.RequestError
+	if errors.As(err, &reqErr) {
+		var schemaErr *openapi3.SchemaError
+		if errors.As(reqErr.Err, &schemaErr) {
+			// Reasons about missing properties already name the field
+			if pointer := schemaErr.JSONPointer(); len(pointer) > 0 && !strings.Contains(schemaErr.Reason, `"`+pointer[len(pointer)-1]+`"`) {
+				return fmt.Sprintf("%s: field %q %s", requestPart(reqErr), strings.Join(pointer, "."), schemaErr.Reason)
+			}
+			return fmt.Sprintf("%s: %s", requestPart(reqErr), schemaErr.Reason)
+		}
+		return reqErr.Error()
+	}
+	var routeErr *routers.RouteError
+	if errors.As(err, &routeErr) {
+		return routeErr.Reason
+	}
+	return err.Error()
+}
+
+// Names the part of the request a validation error is about
+func requestPart(err *openapi3filter.RequestError) string {
+	if err.Parameter != nil {
+		return fmt.Sprintf("%s parameter %q", err.Parameter.In, err.Parameter.Name)
+	}
+	return "request body"
+}
+
+// Decodes an NDJSON body into an array of its records
+func ndjsonBodyDecoder(body io.Reader, _ http.Header, _ *openapi3.SchemaRef, _ openapi3filter.EncodingFn) (any, error) {
+	decoder := json.NewDecoder(bufio.NewReader(body))
+	decoder.UseNumber()
+
+	records := []any{}
+	for {
+		var record any
+		err := decoder.Decode(&record)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Cause: err}
+		}
+		records = append(records, record)
+	}
+}
@@ -0,0 +1,251 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// Schemas of the document and the models they describe
+var schemaModels = map[string]any{
+	"SwiftCodeResponse":           model.SwiftCodeResponse{},
+	"SwiftCodeBranch":             model.SwiftCodeBranch{},
+	"SwiftCodesByCountryResponse": model.SwiftCodesByCountryResponse{},
+	"SwiftCodeMinimalResponse":    model.SwiftCodeMinimalResponse{},
+	"CreateSwiftCodeRequest":      model.CreateSwiftCodeRequest{},
+	"UpdateSwiftCodeRequest":      model.UpdateSwiftCodeRequest{},
+	"BatchLookupRequest":          model.BatchLookupRequest{},
+	"BatchLookupResponse":         model.BatchLookupResponse{},
+	"SwiftCodeSearchResponse":     model.SwiftCodeSearchResponse{},
+	"BulkItemResult":              model.BulkItemResult{},
+	"BulkWriteResponse":           model.BulkWriteResponse{},
+	"Country":                     model.Country{},
+	"CountriesResponse":           model.CountriesResponse{},
+	"SwiftCodeStats":              model.SwiftCodeStats{},
+	"InstitutionResponse":         model.InstitutionResponse{},
+	"InstitutionCountry":          model.InstitutionCountry{},
+	"AuditSnapshot":               model.AuditSnapshot{},
+	"AuditEntry":                  model.AuditEntry{},
+	"AuditLogResponse":            model.AuditLogResponse{},
+	"MessageResponse":             model.MessageResponse{},
+	"ErrorResponse":               model.ErrorResponse{},
+}
+
+// Schemas without a model in internal/model
+var schemasWithoutModel = []string{"BulkRecords", "LogLevel", "GraphQLRequest", "GraphQLResponse"}
+
+// Unit test for Load
+func TestLoad(t *testing.T) {
+	doc, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	assert.NotNil(t, doc.Paths.Find("/api/v1/swift-codes/{swiftCode}"))
+}
+
+// Checks that the schemas of the document list the JSON fields of the models with matching types
+func TestSchemasMatchModels(t *testing.T) {
+	doc, err := Load()
+	assert.NoError(t, err)
+
+	for name := range doc.Components.Schemas {
+		_, hasModel := schemaModels[name]
+		assert.True(t, hasModel || contains(schemasWithoutModel, name), "schema %s describes no model", name)
+	}
+
+	for name, value := range schemaModels {
+		schemaRef, ok := doc.Components.Schemas[name]
+		if !assert.True(t, ok, "model %s is not described", name) {
+			continue
+		}
+		schema := schemaRef.Value
+
+		fields := map[string]string{}
+		typ := reflect.TypeOf(value)
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if tag == "-" {
+				continue
+			}
+			fields[tag] = jsonType(field.Type)
+		}
+
+		for property, propertyRef := range schema.Properties {
+			expected, ok := fields[property]
+			if assert.True(t, ok, "%s.%s is not a field of the model", name, property) {
+				assert.True(t, propertyRef.Value.Type.Is(expected), "%s.%s should be %s", name, property, expected)
+			}
+		}
+		for field := range fields {
+			_, ok := schema.Properties[field]
+			assert.True(t, ok, "%s.%s is not described", name, field)
+		}
+	}
+}
+
+// Returns the JSON schema type a Go type is encoded as
+func jsonType(typ reflect.Type) string {
+	if typ == reflect.TypeOf(time.Time{}) {
+		return "string"
+	}
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return jsonType(typ.Elem())
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return "integer"
+	case reflect.Slice:
+		return "array"
+	case reflect.Struct:
+		return "object"
+	default:
+		return "string"
+	}
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Unit test for Handler
+func TestHandler(t *testing.T) {
+	doc, err := Load()
+	assert.NoError(t, err)
+	h, err := Handler(doc)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var served map[string]any
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &served))
+	assert.Equal(t, "3.0.3", served["openapi"])
+
+	// Swagger UI is served from the embedded files, without loading anything from elsewhere
+	docs := DocsHandler()
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/docs/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `<script src="swagger-ui-bundle.js">`)
+	assert.NotContains(t, rec.Body.String(), "https://")
+
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/docs/swagger-initializer.js", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "../openapi.json")
+
+	for path, contentType := range map[string]string{"swagger-ui-bundle.js": "javascript", "swagger-ui.css": "text/css"} {
+		rec = httptest.NewRecorder()
+		docs.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/docs/"+path, nil))
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+		assert.Contains(t, rec.Header().Get("Content-Type"), contentType, path)
+	}
+
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/docs/README.md", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+// Unit test for Validator
+func TestValidator(t *testing.T) {
+	doc, err := Load()
+	assert.NoError(t, err)
+	validate, err := Validator(doc)
+	assert.NoError(t, err)
+
+	var received string
+	h := validate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		received = string(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		status      int
+		message     string
+	}{
+		{"valid create", http.MethodPost, "/api/v1/swift-codes", "application/json",
+			`{"swiftCode":"TESTUS33XXX","bankName":"Test","address":"Main St","countryISO2":"US","countryName":"USA","isHeadquarter":true}`, http.StatusNoContent, ""},
+		{"create without country name", http.MethodPost, "/api/v1/swift-codes", "application/json",
+			`{"swiftCode":"TESTPLPWXXX","bankName":"Test","address":"Prosta 1","countryISO2":"PL","isHeadquarter":true}`, http.StatusNoContent, ""},
+		{"bad country code", http.MethodPost, "/api/v1/swift-codes", "application/json",
+			`{"swiftCode":"TESTPLPWXXX","bankName":"Test","address":"Prosta 1","countryISO2":"POL","isHeadquarter":true}`, http.StatusBadRequest, `field "countryISO2"`},
+		{"missing field", http.MethodPost, "/api/v1/swift-codes", "application/json",
+			`{"swiftCode":"TESTUS33XXX","address":"Main St","countryISO2":"US","countryName":"USA"}`, http.StatusBadRequest, `property "bankName" is missing`},
+		{"wrong type", http.MethodPost, "/api/v1/swift-codes", "application/json",
+			`{"swiftCode":"TESTUS33XXX","bankName":"Test","address":"Main St","countryISO2":"US","countryName":"USA","isHeadquarter":"yes"}`, http.StatusBadRequest, `field "isHeadquarter"`},
+		{"bad query", http.MethodPost, "/api/v1/swift-codes/bulk?mode=sometimes", "application/json", `[]`, http.StatusBadRequest, `query parameter "mode"`},
+		{"ndjson bulk", http.MethodPost, "/api/v1/swift-codes/bulk?mode=best-effort", "application/x-ndjson", "{\"swiftCode\":\"A\"}\n{}\n", http.StatusNoContent, ""},
+		{"bad export format", http.MethodGet, "/api/v1/swift-codes/export?format=xml", "", "", http.StatusBadRequest, `query parameter "format"`},
+		{"graphql without query", http.MethodPost, "/api/v1/graphql", "application/json", `{"variables":{}}`, http.StatusBadRequest, `property "query" is missing`},
+		{"bad institution code", http.MethodGet, "/api/v1/institutions/TPEOPLPW", "", "", http.StatusBadRequest, `parameter "bic4"`},
+		{"lookup", http.MethodGet, "/api/v1/swift-codes/TESTUS33XXX", "", "", http.StatusNoContent, ""},
+		{"undocumented route", http.MethodGet, "/api/v1/unknown", "", "", http.StatusNoContent, ""},
+		{"undocumented method", http.MethodPatch, "/api/v1/swift-codes/TESTUS33XXX", "", "", http.StatusNoContent, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = ""
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+			if tt.status == http.StatusBadRequest {
+				// Validation errors have the body of every other API error
+				var body model.ErrorResponse
+				assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+				assert.True(t, strings.HasPrefix(body.Message, "Invalid request: "), body.Message)
+				assert.Contains(t, body.Message, tt.message)
+			}
+			if tt.status == http.StatusNoContent {
+				// The body is still readable by the handler
+				assert.Equal(t, tt.body, received)
+			}
+		})
+	}
+
+	// Bodies over the limit set by earlier middleware are too large rather than invalid
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/swift-codes/batch-lookup", strings.NewReader(`{"swiftCodes":["TESTUS33XXX"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	req.Body = http.MaxBytesReader(rec, req.Body, 10)
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	// Validation errors are rendered as XML for clients preferring it
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/swift-codes/export?format=xml", nil)
+	req.Header.Set("Accept", "application/xml")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "application/xml", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "<error><message>Invalid request: ")
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dodskygge/go_swift/internal/model"
+)
+
+type MySQLAPIKeyRepository struct {
+	DB *sql.DB
+}
+
+// Stores a new API key and returns its ID
+func (repo *MySQLAPIKeyRepository) Create(ctx context.Context, key *model.APIKeyEntity) (id int64, err error) {
+	ctx, done := trackQuery(ctx, "CreateAPIKey")
+	defer func() { done(1, err) }()
+
+	query := `
+        INSERT INTO api_keys (name, key_prefix, key_hash, scopes, created_at)
+        VALUES (?, ?, ?, ?, ?)
+    `
+	result, err := repo.DB.ExecContext(ctx, query,
+		key.Name,
+		key.KeyPrefix,
+		key.KeyHash,
+		strings.Join(key.Scopes, ","),
+		key.CreatedAt,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute insert query: %w", err)
+	}
+
+	id, err = result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read inserted id: %w", err)
+	}
+
+	return id, nil
+}
+
+// Retrieves an API key by the hash of its value
+func (repo *MySQLAPIKeyRepository) GetByHash(ctx context.Context, hash string) (key *model.APIKeyEntity, err error) {
+	ctx, done := trackQuery(ctx, "GetAPIKeyByHash")
+	defer func() {
+		rows := 0
+		if key != nil {
+			rows = 1
+		}
+		done(rows, err)
+	}()
+
+	query := `
+        SELECT id, name, key_prefix, key_hash, scopes, created_at, revoked_at
+        FROM api_keys
+        WHERE key_hash = ?
+    `
+	key, err = scanAPIKey(repo.DB.QueryRowContext(ctx, query, hash))
+	if err == sql.ErrNoRows {
+		return nil, nil // No result found
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Retrieves all API keys, including revoked ones
+func (repo *MySQLAPIKeyRepository) List(ctx context.Context) (keys []*model.APIKeyEntity, err error) {
+	ctx, done := trackQuery(ctx, "ListAPIKeys")
+	defer func() { done(len(keys), err) }()
+
+	query := `
+        SELECT id, name, key_prefix, key_hash, scopes, created_at, revoked_at
+        FROM api_keys
+        ORDER BY id
+    `
+	rows, err := repo.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Marks an API key as revoked
+func (repo *MySQLAPIKeyRepository) Revoke(ctx context.Context, id int64) (err error) {
+	ctx, done := trackQuery(ctx, "RevokeAPIKey")
+	defer func() { done(1, err) }()
+
+	query := `
+        UPDATE api_keys
+        SET revoked_at = ?
+        WHERE id = ? AND revoked_at IS NULL
+    `
+	result, err := repo.DB.ExecContext(ctx, query, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to execute update query: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no active API key found with id %d", id)
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// Scans an api_keys row into an entity
+func scanAPIKey(row rowScanner) (*model.APIKeyEntity, error) {
+	key := new(model.APIKeyEntity)
+	var scopes string
+	var revokedAt sql.NullTime
+	err := row.Scan(
+		&key.ID,
+		&key.Name,
+		&key.KeyPrefix,
+		&key.KeyHash,
+		&scopes,
+		&key.CreatedAt,
+		&revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if scopes != "" {
+		key.Scopes = strings.Split(scopes, ",")
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return key, nil
+}
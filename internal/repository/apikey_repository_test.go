@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// Helper function to set up the api_keys table
+func setupAPIKeyTable(t *testing.T, db *sql.DB) {
+	_, err := db.Exec(`
+        CREATE TABLE api_keys (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            name TEXT NOT NULL,
+            key_prefix TEXT NOT NULL,
+            key_hash TEXT NOT NULL UNIQUE,
+            scopes TEXT NOT NULL,
+            created_at DATETIME NOT NULL,
+            revoked_at DATETIME
+        )
+    `)
+	assert.NoError(t, err)
+}
+
+// Unit test for the API key lifecycle
+func TestAPIKeyRepository(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	setupAPIKeyTable(t, db)
+
+	repo := &MySQLAPIKeyRepository{DB: db}
+	ctx := context.Background()
+
+	// Test Create
+	id, err := repo.Create(ctx, &model.APIKeyEntity{
+		Name:      "reconciliation",
+		KeyPrefix: "gsk_abcdefgh",
+		KeyHash:   "hash-1",
+		Scopes:    []string{"codes:read", "codes:write"},
+		CreatedAt: time.Now().UTC(),
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, id)
+
+	// Test GetByHash
+	key, err := repo.GetByHash(ctx, "hash-1")
+	assert.NoError(t, err)
+	assert.NotNil(t, key)
+	assert.Equal(t, "reconciliation", key.Name)
+	assert.Equal(t, []string{"codes:read", "codes:write"}, key.Scopes)
+	assert.Nil(t, key.RevokedAt)
+
+	// Test non-existent hash
+	key, err = repo.GetByHash(ctx, "unknown")
+	assert.NoError(t, err)
+	assert.Nil(t, key)
+
+	// Test Revoke
+	assert.NoError(t, repo.Revoke(ctx, id))
+	assert.Error(t, repo.Revoke(ctx, id))
+
+	// Test List
+	keys, err := repo.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assert.NotNil(t, keys[0].RevokedAt)
+}
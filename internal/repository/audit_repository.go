@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dodskygge/go_swift/internal/auth"
+	"github.com/dodskygge/go_swift/internal/logging"
+	"github.com/dodskygge/go_swift/internal/model"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Actor recorded for changes made without an authenticated principal
+const anonymousActor = "anonymous"
+
+// Length of the actor column; principal IDs built from JWT subjects or certificate names may be longer
+const maxAuditActorLength = 255
+
+// Records a change of a SWIFT code in the audit log within the transaction of the change, with the
+// principal and request ID carried by ctx; before is nil for creations and after for deletions
+func insertAuditEntry(ctx context.Context, tx *sql.Tx, action, swiftCode string, before, after *model.SwiftEntity) error {
+	actor := anonymousActor
+	if p := auth.PrincipalFromContext(ctx); p != nil {
+		actor = truncate(p.ID, maxAuditActorLength)
+	}
+
+	beforeData, err := auditData(before)
+	if err != nil {
+		return err
+	}
+	afterData, err := auditData(after)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        INSERT INTO audit_log (created_at, actor, action, swift_code, before_data, after_data, request_id)
+        VALUES (?, ?, ?, ?, ?, ?, ?)
+    `
+	_, err = tx.ExecContext(ctx, query,
+		time.Now().UTC(),
+		actor,
+		action,
+		swiftCode,
+		beforeData,
+		afterData,
+		truncate(logging.RequestIDFromContext(ctx), logging.MaxRequestIDLength),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Shortens s to at most n characters to fit a column
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// Encodes the state of an entity as stored in the audit log, NULL when there is none
+func auditData(entity *model.SwiftEntity) (sql.NullString, error) {
+	if entity == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(model.AuditSnapshot{
+		Address:       entity.Address,
+		BankName:      entity.BankName,
+		CountryISO2:   entity.CountryISO2,
+		CountryName:   entity.CountryName,
+		IsHeadquarter: entity.IsHeadquarter,
+		SwiftCode:     entity.SwiftCode,
+		Version:       entity.Version,
+	})
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to encode audit data: %w", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// Retrieves the audit log entries matching the query, ordered by ID
+func (repo *MySQLSwiftRepository) GetAuditLog(ctx context.Context, query model.AuditQuery) (entries []model.AuditEntry, err error) {
+	ctx, done := trackQuery(ctx, "GetAuditLog",
+		attribute.String("swift.code", query.SwiftCode),
+		attribute.String("audit.actor", query.Actor),
+	)
+	defer func() { done(len(entries), err) }()
+
+	var conditions []string
+	var args []any
+	if query.SwiftCode != "" {
+		conditions = append(conditions, "swift_code = ?")
+		args = append(args, query.SwiftCode)
+	}
+	if query.Actor != "" {
+		conditions = append(conditions, "actor = ?")
+		args = append(args, query.Actor)
+	}
+	if query.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, query.Action)
+	}
+	if !query.Since.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, query.Since.UTC())
+	}
+	if !query.Until.IsZero() {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, query.Until.UTC())
+	}
+	if query.After > 0 {
+		conditions = append(conditions, "id > ?")
+		args = append(args, query.After)
+	}
+
+	sqlQuery := `
+        SELECT id, created_at, actor, action, swift_code, before_data, after_data, request_id
+        FROM audit_log
+    `
+	if len(conditions) > 0 {
+		sqlQuery += "WHERE " + strings.Join(conditions, " AND ") + "\n"
+	}
+	sqlQuery += "ORDER BY id LIMIT ?"
+	args = append(args, query.Limit)
+
+	rows, err := repo.DB.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry model.AuditEntry
+		var beforeData, afterData sql.NullString
+		err := rows.Scan(
+			&entry.ID,
+			&entry.Time,
+			&entry.Actor,
+			&entry.Action,
+			&entry.SwiftCode,
+			&beforeData,
+			&afterData,
+			&entry.RequestID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if entry.Before, err = auditSnapshot(beforeData); err != nil {
+			return nil, err
+		}
+		if entry.After, err = auditSnapshot(afterData); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Decodes a state stored by auditData
+func auditSnapshot(data sql.NullString) (*model.AuditSnapshot, error) {
+	if !data.Valid {
+		return nil, nil
+	}
+	snapshot := new(model.AuditSnapshot)
+	if err := json.Unmarshal([]byte(data.String), snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode audit data: %w", err)
+	}
+	return snapshot, nil
+}
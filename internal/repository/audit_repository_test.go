@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dodskygge/go_swift/internal/auth"
+	"github.com/dodskygge/go_swift/internal/logging"
+	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// Unit test for the audit log written by Create, Update, Delete and BulkWrite
+func TestAuditLog(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := &MySQLSwiftRepository{DB: db}
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "apikey:1", Name: "ops"})
+	ctx = logging.WithRequestID(ctx, "req-1")
+
+	entity := &model.SwiftEntity{
+		SwiftCode:     "TESTUS33XXX",
+		BankName:      "Test Bank",
+		Address:       "123 Main St",
+		CountryISO2:   "US",
+		CountryName:   "UNITED STATES",
+		IsHeadquarter: true,
+	}
+	assert.NoError(t, repo.Create(ctx, entity))
+
+	// A failed change leaves no trace
+	assert.ErrorIs(t, repo.Update(ctx, entity, 2), model.ErrVersionMismatch)
+
+	entity.BankName = "Renamed Bank"
+	assert.NoError(t, repo.Update(ctx, entity, 1))
+
+	// Changes without a principal are recorded as anonymous
+	results, committed, err := repo.BulkWrite(context.Background(), []*model.SwiftEntity{
+		{SwiftCode: "TESTPLPWXXX", BankName: "Test Bank PL", Address: "1 Prosta", CountryISO2: "PL", CountryName: "POLAND", IsHeadquarter: true},
+	}, false, true)
+	assert.NoError(t, err)
+	assert.True(t, committed)
+	assert.Equal(t, model.BulkStatusCreated, results[0].Status)
+
+	assert.NoError(t, repo.Delete(ctx, "TESTUS33XXX", 0))
+
+	entries, err := repo.GetAuditLog(context.Background(), model.AuditQuery{SwiftCode: "TESTUS33XXX", Limit: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 3) {
+		assert.Equal(t, model.AuditActionCreate, entries[0].Action)
+		assert.Equal(t, "apikey:1", entries[0].Actor)
+		assert.Equal(t, "req-1", entries[0].RequestID)
+		assert.False(t, entries[0].Time.IsZero())
+		assert.Nil(t, entries[0].Before)
+		assert.Equal(t, &model.AuditSnapshot{
+			Address:       "123 Main St",
+			BankName:      "Test Bank",
+			CountryISO2:   "US",
+			CountryName:   "UNITED STATES",
+			IsHeadquarter: true,
+			SwiftCode:     "TESTUS33XXX",
+			Version:       1,
+		}, entries[0].After)
+
+		assert.Equal(t, model.AuditActionUpdate, entries[1].Action)
+		assert.Equal(t, "Test Bank", entries[1].Before.BankName)
+		assert.Equal(t, "Renamed Bank", entries[1].After.BankName)
+		assert.Equal(t, int64(2), entries[1].After.Version)
+
+		assert.Equal(t, model.AuditActionDelete, entries[2].Action)
+		assert.Equal(t, "Renamed Bank", entries[2].Before.BankName)
+		assert.Nil(t, entries[2].After)
+	}
+
+	// Filters and paging
+	entries, err = repo.GetAuditLog(context.Background(), model.AuditQuery{Actor: "anonymous", Limit: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "TESTPLPWXXX", entries[0].SwiftCode)
+	}
+
+	entries, err = repo.GetAuditLog(context.Background(), model.AuditQuery{Action: model.AuditActionCreate, Limit: 1})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "TESTUS33XXX", entries[0].SwiftCode)
+		entries, err = repo.GetAuditLog(context.Background(), model.AuditQuery{Action: model.AuditActionCreate, After: entries[0].ID, Limit: 1})
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "TESTPLPWXXX", entries[0].SwiftCode)
+	}
+
+	entries, err = repo.GetAuditLog(context.Background(), model.AuditQuery{Since: time.Now().Add(-time.Hour), Until: time.Now().Add(time.Hour), Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 4)
+
+	entries, err = repo.GetAuditLog(context.Background(), model.AuditQuery{Since: time.Now().Add(time.Hour), Limit: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+// Unit test for the audit log entries of changes with the longest request ID and an overlong principal ID
+func TestAuditLogLongValues(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := &MySQLSwiftRepository{DB: db}
+	requestID := strings.Repeat("r", logging.MaxRequestIDLength)
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "cert:" + strings.Repeat("é", 300)})
+	ctx = logging.WithRequestID(ctx, requestID)
+
+	err := repo.Create(ctx, &model.SwiftEntity{
+		SwiftCode: "TESTUS33XXX", BankName: "Test Bank", Address: "123 Main St", CountryISO2: "US", CountryName: "UNITED STATES", IsHeadquarter: true,
+	})
+	assert.NoError(t, err)
+
+	entries, err := repo.GetAuditLog(context.Background(), model.AuditQuery{SwiftCode: "TESTUS33XXX", Limit: 1})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, requestID, entries[0].RequestID)
+		assert.Equal(t, maxAuditActorLength, utf8.RuneCountInString(entries[0].Actor))
+		assert.True(t, strings.HasPrefix(entries[0].Actor, "cert:é"))
+	}
+}
@@ -3,24 +3,54 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
+	"time"
 
+	"github.com/dodskygge/go_swift/internal/metrics"
 	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/dodskygge/go_swift/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type MySQLSwiftRepository struct {
 	DB *sql.DB
 }
 
-// Retrieves a SWIFT code by its value
-func (repo *MySQLSwiftRepository) GetBySwiftCode(ctx context.Context, swiftCode string) (*model.SwiftEntity, error) {
-	query := `
-        SELECT swift_code, name, address, country_iso2_code, country_name, is_headquarter
-        FROM banks
-        WHERE swift_code = ?
-    `
-	row := repo.DB.QueryRowContext(ctx, query, swiftCode)
+// Starts a span for a repository query; the returned function logs the outcome,
+// records the duration metric and ends the span
+func trackQuery(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, func(rows int, err error)) {
+	start := time.Now()
+	ctx, span := tracing.Tracer().Start(ctx, "MySQLSwiftRepository."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.system", "mysql"), attribute.String("db.operation", method)),
+		trace.WithAttributes(attrs...),
+	)
+
+	return ctx, func(rows int, err error) {
+		duration := time.Since(start)
+		metrics.ObserveQuery(method, duration, err)
+		span.SetAttributes(attribute.Int("db.rows", rows))
+		tracing.End(span, err)
 
+		if errors.Is(err, model.ErrNotFound) || errors.Is(err, model.ErrVersionMismatch) {
+			slog.DebugContext(ctx, "query precondition failed", "method", method, "duration", duration, "error", err)
+			return
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "query failed", "method", method, "duration", duration, "error", err)
+			return
+		}
+		slog.DebugContext(ctx, "query executed", "method", method, "duration", duration, "rows", rows)
+	}
+}
+
+// Scans a banks row selected as swift_code, name, address, country_iso2_code, country_name,
+// is_headquarter, updated_at, version into an entity
+func scanSwiftEntity(row rowScanner) (*model.SwiftEntity, error) {
 	entity := new(model.SwiftEntity)
 	err := row.Scan(
 		&entity.SwiftCode,
@@ -29,8 +59,34 @@ func (repo *MySQLSwiftRepository) GetBySwiftCode(ctx context.Context, swiftCode
 		&entity.CountryISO2,
 		&entity.CountryName,
 		&entity.IsHeadquarter,
+		&entity.UpdatedAt,
+		&entity.Version,
 	)
+	if err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
 
+// Retrieves a SWIFT code by its value
+func (repo *MySQLSwiftRepository) GetBySwiftCode(ctx context.Context, swiftCode string) (entity *model.SwiftEntity, err error) {
+	ctx, done := trackQuery(ctx, "GetBySwiftCode", attribute.String("swift.code", swiftCode))
+	defer func() {
+		rows := 0
+		if entity != nil {
+			rows = 1
+		}
+		done(rows, err)
+	}()
+
+	query := `
+        SELECT swift_code, name, address, country_iso2_code, country_name, is_headquarter, updated_at, version
+        FROM banks
+        WHERE swift_code = ?
+    `
+	row := repo.DB.QueryRowContext(ctx, query, swiftCode)
+
+	entity, err = scanSwiftEntity(row)
 	if err == sql.ErrNoRows {
 		return nil, nil // No result found
 	}
@@ -42,9 +98,12 @@ func (repo *MySQLSwiftRepository) GetBySwiftCode(ctx context.Context, swiftCode
 }
 
 // Retrieves all branches for a given headquarters SWIFT code
-func (repo *MySQLSwiftRepository) GetBranchesByHqSwiftCode(ctx context.Context, hqCode string) ([]*model.SwiftEntity, error) {
+func (repo *MySQLSwiftRepository) GetBranchesByHqSwiftCode(ctx context.Context, hqCode string) (entities []*model.SwiftEntity, err error) {
+	ctx, done := trackQuery(ctx, "GetBranchesByHqSwiftCode", attribute.String("swift.hq_prefix", hqCode))
+	defer func() { done(len(entities), err) }()
+
 	query := `
-        SELECT swift_code, name, address, country_iso2_code, country_name, is_headquarter
+        SELECT swift_code, name, address, country_iso2_code, country_name, is_headquarter, updated_at, version
         FROM banks
         WHERE swift_code LIKE ? AND is_headquarter = FALSE
     `
@@ -54,17 +113,48 @@ func (repo *MySQLSwiftRepository) GetBranchesByHqSwiftCode(ctx context.Context,
 	}
 	defer rows.Close()
 
-	var entities []*model.SwiftEntity
 	for rows.Next() {
-		entity := new(model.SwiftEntity)
-		err := rows.Scan(
-			&entity.SwiftCode,
-			&entity.BankName,
-			&entity.Address,
-			&entity.CountryISO2,
-			&entity.CountryName,
-			&entity.IsHeadquarter,
-		)
+		entity, err := scanSwiftEntity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		entities = append(entities, entity)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return entities, nil
+}
+
+// Retrieves the branches of many headquarters, given by their 8-character prefixes, with a single query
+func (repo *MySQLSwiftRepository) GetBranchesByHqSwiftCodes(ctx context.Context, hqCodes []string) (entities []*model.SwiftEntity, err error) {
+	ctx, done := trackQuery(ctx, "GetBranchesByHqSwiftCodes", attribute.Int("swift.hq_prefixes", len(hqCodes)))
+	defer func() { done(len(entities), err) }()
+
+	if len(hqCodes) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, len(hqCodes))
+	for i, code := range hqCodes {
+		args[i] = code + "%"
+	}
+	query := `
+        SELECT swift_code, name, address, country_iso2_code, country_name, is_headquarter, updated_at, version
+        FROM banks
+        WHERE (swift_code LIKE ?` + strings.Repeat(" OR swift_code LIKE ?", len(hqCodes)-1) + `) AND is_headquarter = FALSE
+        ORDER BY swift_code
+    `
+	rows, err := repo.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entity, err := scanSwiftEntity(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
@@ -79,9 +169,12 @@ func (repo *MySQLSwiftRepository) GetBranchesByHqSwiftCode(ctx context.Context,
 }
 
 // Retrieves all SWIFT codes for a given country
-func (repo *MySQLSwiftRepository) GetByCountry(ctx context.Context, countryISO2 string) ([]*model.SwiftEntity, error) {
+func (repo *MySQLSwiftRepository) GetByCountry(ctx context.Context, countryISO2 string) (entities []*model.SwiftEntity, err error) {
+	ctx, done := trackQuery(ctx, "GetByCountry", attribute.String("swift.country_iso2", countryISO2))
+	defer func() { done(len(entities), err) }()
+
 	query := `
-        SELECT swift_code, name, address, country_iso2_code, country_name, is_headquarter
+        SELECT swift_code, name, address, country_iso2_code, country_name, is_headquarter, updated_at, version
         FROM banks
         WHERE country_iso2_code = ?
     `
@@ -91,17 +184,8 @@ func (repo *MySQLSwiftRepository) GetByCountry(ctx context.Context, countryISO2
 	}
 	defer rows.Close()
 
-	var entities []*model.SwiftEntity
 	for rows.Next() {
-		entity := new(model.SwiftEntity)
-		err := rows.Scan(
-			&entity.SwiftCode,
-			&entity.BankName,
-			&entity.Address,
-			&entity.CountryISO2,
-			&entity.CountryName,
-			&entity.IsHeadquarter,
-		)
+		entity, err := scanSwiftEntity(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
@@ -115,13 +199,414 @@ func (repo *MySQLSwiftRepository) GetByCountry(ctx context.Context, countryISO2
 	return entities, nil
 }
 
-// Creates a new SWIFT code entry
-func (repo *MySQLSwiftRepository) Create(ctx context.Context, swift *model.SwiftEntity) error {
+// Retrieves the SWIFT codes of an institution, whose first 4 characters are bic4, ordered by country and code
+func (repo *MySQLSwiftRepository) GetByInstitution(ctx context.Context, bic4 string) (entities []*model.SwiftEntity, err error) {
+	ctx, done := trackQuery(ctx, "GetByInstitution", attribute.String("swift.institution", bic4))
+	defer func() { done(len(entities), err) }()
+
+	query := `
+        SELECT swift_code, name, address, country_iso2_code, country_name, is_headquarter, updated_at, version
+        FROM banks
+        WHERE swift_code LIKE ?
+        ORDER BY country_iso2_code, swift_code
+    `
+	rows, err := repo.DB.QueryContext(ctx, query, bic4+"%")
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entity, err := scanSwiftEntity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		entities = append(entities, entity)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return entities, nil
+}
+
+// Retrieves the SWIFT codes among the given values with a single query
+func (repo *MySQLSwiftRepository) GetBySwiftCodes(ctx context.Context, swiftCodes []string) (entities []*model.SwiftEntity, err error) {
+	ctx, done := trackQuery(ctx, "GetBySwiftCodes", attribute.Int("swift.codes_requested", len(swiftCodes)))
+	defer func() { done(len(entities), err) }()
+
+	if len(swiftCodes) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, len(swiftCodes))
+	for i, code := range swiftCodes {
+		args[i] = code
+	}
+	query := `
+        SELECT swift_code, name, address, country_iso2_code, country_name, is_headquarter, updated_at, version
+        FROM banks
+        WHERE swift_code IN (?` + strings.Repeat(", ?", len(swiftCodes)-1) + `)
+    `
+	rows, err := repo.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entity, err := scanSwiftEntity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		entities = append(entities, entity)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return entities, nil
+}
+
+// Streams the SWIFT codes matching the filter ordered by code, calling fn for each row
+// without loading the whole table; an error from fn stops the iteration and is returned
+func (repo *MySQLSwiftRepository) ForEach(ctx context.Context, filter model.SwiftCodeFilter, fn func(*model.SwiftEntity) error) (err error) {
+	ctx, done := trackQuery(ctx, "ForEach", attribute.String("swift.country_iso2", filter.CountryISO2))
+	count := 0
+	defer func() { done(count, err) }()
+
+	var conditions []string
+	var args []any
+	if filter.CountryISO2 != "" {
+		conditions = append(conditions, "country_iso2_code = ?")
+		args = append(args, filter.CountryISO2)
+	}
+	if filter.IsHeadquarter != nil {
+		conditions = append(conditions, "is_headquarter = ?")
+		args = append(args, *filter.IsHeadquarter)
+	}
+
+	query := `
+        SELECT swift_code, name, address, country_iso2_code, country_name, is_headquarter, updated_at, version
+        FROM banks
+    `
+	if len(conditions) > 0 {
+		query += "WHERE " + strings.Join(conditions, " AND ") + "\n"
+	}
+	query += "ORDER BY swift_code"
+
+	rows, err := repo.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entity, err := scanSwiftEntity(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		if err := fn(entity); err != nil {
+			return err
+		}
+		count++
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return nil
+}
+
+// Searches SWIFT codes by code prefix or bank name, returning at most search.Limit codes ordered by code
+func (repo *MySQLSwiftRepository) Search(ctx context.Context, search model.SwiftCodeSearch) (entities []*model.SwiftEntity, err error) {
+	ctx, done := trackQuery(ctx, "Search", attribute.String("swift.country_iso2", search.Filter.CountryISO2))
+	defer func() { done(len(entities), err) }()
+
+	var conditions []string
+	var args []any
+	if search.Query != "" {
+		// Wildcards in the query match literally
+		escaped := likeEscaper.Replace(search.Query)
+		conditions = append(conditions, "(swift_code LIKE ? ESCAPE '!' OR name LIKE ? ESCAPE '!')")
+		args = append(args, escaped+"%", "%"+escaped+"%")
+	}
+	if search.Filter.CountryISO2 != "" {
+		conditions = append(conditions, "country_iso2_code = ?")
+		args = append(args, search.Filter.CountryISO2)
+	}
+	if search.Filter.IsHeadquarter != nil {
+		conditions = append(conditions, "is_headquarter = ?")
+		args = append(args, *search.Filter.IsHeadquarter)
+	}
+	if search.After != "" {
+		conditions = append(conditions, "swift_code > ?")
+		args = append(args, search.After)
+	}
+
+	query := `
+        SELECT swift_code, name, address, country_iso2_code, country_name, is_headquarter, updated_at, version
+        FROM banks
+    `
+	if len(conditions) > 0 {
+		query += "WHERE " + strings.Join(conditions, " AND ") + "\n"
+	}
+	query += "ORDER BY swift_code LIMIT ?"
+	args = append(args, search.Limit)
+
+	rows, err := repo.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entity, err := scanSwiftEntity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		entities = append(entities, entity)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return entities, nil
+}
+
+// Escapes LIKE wildcards with the ESCAPE character used by Search
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// Creates a new SWIFT code entry, recording it in the audit log
+func (repo *MySQLSwiftRepository) Create(ctx context.Context, swift *model.SwiftEntity) (err error) {
+	ctx, done := trackQuery(ctx, "Create", attribute.String("swift.code", swift.SwiftCode))
+	defer func() { done(1, err) }()
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = insertSwiftEntity(ctx, tx, swift); err != nil {
+		return err
+	}
+	after, err := selectSwiftEntity(ctx, tx, swift.SwiftCode)
+	if err != nil {
+		return err
+	}
+	if err = insertAuditEntry(ctx, tx, model.AuditActionCreate, swift.SwiftCode, nil, after); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Writes the entities in a single transaction, inserting new codes and, with upsert, updating existing ones.
+// With atomic the transaction is rolled back at the first failing entity and committed reports false;
+// otherwise failing entities are reported and the others committed. Results are in the order of entities.
+// Every write is recorded in the audit log; failing to record one fails the whole transaction.
+func (repo *MySQLSwiftRepository) BulkWrite(ctx context.Context, entities []*model.SwiftEntity, upsert, atomic bool) (results []model.BulkItemResult, committed bool, err error) {
+	ctx, done := trackQuery(ctx, "BulkWrite",
+		attribute.Int("swift.codes_requested", len(entities)),
+		attribute.Bool("bulk.upsert", upsert),
+		attribute.Bool("bulk.atomic", atomic),
+	)
+	defer func() { done(len(results), err) }()
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	results = make([]model.BulkItemResult, len(entities))
+	for i, entity := range entities {
+		results[i] = model.BulkItemResult{Index: i, SwiftCode: entity.SwiftCode}
+
+		status, before, writeErr := writeSwiftEntity(ctx, tx, entity, upsert)
+		if writeErr == nil {
+			after, err := selectSwiftEntity(ctx, tx, entity.SwiftCode)
+			if err != nil {
+				return nil, false, err
+			}
+			action := model.AuditActionCreate
+			if status == model.BulkStatusUpdated {
+				action = model.AuditActionUpdate
+			}
+			if err := insertAuditEntry(ctx, tx, action, entity.SwiftCode, before, after); err != nil {
+				return nil, false, err
+			}
+			results[i].Status = status
+			continue
+		}
+
+		results[i].Status = model.BulkStatusFailed
+		results[i].Error = writeErr.Error()
+		if atomic {
+			for j := range results {
+				if j != i {
+					results[j] = model.BulkItemResult{Index: j, SwiftCode: entities[j].SwiftCode, Status: model.BulkStatusNotApplied}
+				}
+			}
+			return results, false, nil
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return results, true, nil
+}
+
+// Inserts or, with upsert, updates a single entity within a transaction and returns the bulk status
+// and the entity it replaced, nil when it was inserted
+func writeSwiftEntity(ctx context.Context, tx *sql.Tx, swift *model.SwiftEntity, upsert bool) (string, *model.SwiftEntity, error) {
+	before, err := selectSwiftEntity(ctx, tx, swift.SwiftCode)
+	if err != nil {
+		return "", nil, err
+	}
+	if before == nil {
+		if err := insertSwiftEntity(ctx, tx, swift); err != nil {
+			return "", nil, err
+		}
+		return model.BulkStatusCreated, nil, nil
+	}
+	if !upsert {
+		return "", nil, model.ErrAlreadyExists
+	}
+
+	if err := updateSwiftEntity(ctx, tx, swift, before.Version); err != nil {
+		return "", nil, err
+	}
+	return model.BulkStatusUpdated, before, nil
+}
+
+// Updates a SWIFT code entry if it is still at the expected version, 0 updates any version,
+// recording the change in the audit log
+func (repo *MySQLSwiftRepository) Update(ctx context.Context, swift *model.SwiftEntity, expectedVersion int64) (err error) {
+	ctx, done := trackQuery(ctx, "Update",
+		attribute.String("swift.code", swift.SwiftCode),
+		attribute.Int64("swift.expected_version", expectedVersion),
+	)
+	defer func() { done(1, err) }()
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := selectExpectedVersion(ctx, tx, swift.SwiftCode, expectedVersion)
+	if err != nil {
+		return err
+	}
+	if err = updateSwiftEntity(ctx, tx, swift, before.Version); err != nil {
+		return err
+	}
+	after, err := selectSwiftEntity(ctx, tx, swift.SwiftCode)
+	if err != nil {
+		return err
+	}
+	if err = insertAuditEntry(ctx, tx, model.AuditActionUpdate, swift.SwiftCode, before, after); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Deletes a SWIFT code entry if it is still at the expected version, 0 deletes any version,
+// recording the deletion in the audit log
+func (repo *MySQLSwiftRepository) Delete(ctx context.Context, swiftCode string, expectedVersion int64) (err error) {
+	ctx, done := trackQuery(ctx, "Delete",
+		attribute.String("swift.code", swiftCode),
+		attribute.Int64("swift.expected_version", expectedVersion),
+	)
+	defer func() { done(1, err) }()
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := selectExpectedVersion(ctx, tx, swiftCode, expectedVersion)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        DELETE FROM banks
+        WHERE swift_code = ? AND version = ?
+    `
+	result, err := tx.ExecContext(ctx, query, swiftCode, before.Version)
+	if err != nil {
+		return fmt.Errorf("failed to execute delete query: %w", err)
+	}
+	if err = checkVersionedWrite(result); err != nil {
+		return err
+	}
+	if err = insertAuditEntry(ctx, tx, model.AuditActionDelete, swiftCode, before, nil); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Retrieves a SWIFT code within a transaction, nil when it does not exist
+func selectSwiftEntity(ctx context.Context, tx *sql.Tx, swiftCode string) (*model.SwiftEntity, error) {
+	query := `
+        SELECT swift_code, name, address, country_iso2_code, country_name, is_headquarter, updated_at, version
+        FROM banks
+        WHERE swift_code = ?
+    `
+	entity, err := scanSwiftEntity(tx.QueryRowContext(ctx, query, swiftCode))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SWIFT code: %w", err)
+	}
+	return entity, nil
+}
+
+// Retrieves a SWIFT code within a transaction, returning model.ErrNotFound when it does not exist and
+// model.ErrVersionMismatch when it is not at the expected version, 0 matching any version
+func selectExpectedVersion(ctx context.Context, tx *sql.Tx, swiftCode string, expectedVersion int64) (*model.SwiftEntity, error) {
+	entity, err := selectSwiftEntity(ctx, tx, swiftCode)
+	if err != nil {
+		return nil, err
+	}
+	if entity == nil {
+		return nil, model.ErrNotFound
+	}
+	if expectedVersion != 0 && entity.Version != expectedVersion {
+		return nil, model.ErrVersionMismatch
+	}
+	return entity, nil
+}
+
+// Inserts a new SWIFT code within a transaction
+func insertSwiftEntity(ctx context.Context, tx *sql.Tx, swift *model.SwiftEntity) error {
 	query := `
         INSERT INTO banks (swift_code, name, address, country_iso2_code, country_name, is_headquarter)
         VALUES (?, ?, ?, ?, ?, ?)
     `
-	_, err := repo.DB.ExecContext(ctx, query,
+	_, err := tx.ExecContext(ctx, query,
 		swift.SwiftCode,
 		swift.BankName,
 		swift.Address,
@@ -132,28 +617,154 @@ func (repo *MySQLSwiftRepository) Create(ctx context.Context, swift *model.Swift
 	if err != nil {
 		return fmt.Errorf("failed to execute insert query: %w", err)
 	}
-
 	return nil
 }
 
-// Deletes a SWIFT code entry
-func (repo *MySQLSwiftRepository) Delete(ctx context.Context, swiftCode string) error {
+// Updates a SWIFT code within a transaction provided it is still at version, which it was read at
+func updateSwiftEntity(ctx context.Context, tx *sql.Tx, swift *model.SwiftEntity, version int64) error {
 	query := `
-        DELETE FROM banks
-        WHERE swift_code = ?
+        UPDATE banks
+        SET name = ?, address = ?, country_iso2_code = ?, country_name = ?,
+            version = version + 1, updated_at = CURRENT_TIMESTAMP
+        WHERE swift_code = ? AND version = ?
     `
-	result, err := repo.DB.ExecContext(ctx, query, swiftCode)
+	result, err := tx.ExecContext(ctx, query,
+		swift.BankName,
+		swift.Address,
+		swift.CountryISO2,
+		swift.CountryName,
+		swift.SwiftCode,
+		version,
+	)
 	if err != nil {
-		return fmt.Errorf("failed to execute delete query: %w", err)
+		return fmt.Errorf("failed to execute update query: %w", err)
 	}
+	return checkVersionedWrite(result)
+}
 
+// Reports model.ErrVersionMismatch when a statement conditioned on the version read earlier in the
+// transaction affected no rows because another request changed the code in between
+func checkVersionedWrite(result sql.Result) error {
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to check rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("no SWIFT code found with the given value")
+		return model.ErrVersionMismatch
 	}
-
 	return nil
 }
+
+// Counts SWIFT codes per country
+func (repo *MySQLSwiftRepository) CountByCountry(ctx context.Context) (counts map[string]int, err error) {
+	ctx, done := trackQuery(ctx, "CountByCountry")
+	defer func() { done(len(counts), err) }()
+
+	query := `
+        SELECT country_iso2_code, COUNT(*)
+        FROM banks
+        GROUP BY country_iso2_code
+    `
+	rows, err := repo.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	counts = make(map[string]int)
+	for rows.Next() {
+		var country string
+		var count int
+		if err := rows.Scan(&country, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		counts[country] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return counts, nil
+}
+
+// Lists the ISO 3166 reference countries ordered by code, with the number of SWIFT codes of each
+func (repo *MySQLSwiftRepository) ListCountries(ctx context.Context) (countries []*model.Country, err error) {
+	ctx, done := trackQuery(ctx, "ListCountries")
+	defer func() { done(len(countries), err) }()
+
+	query := `
+        SELECT c.iso2, c.iso3, c.numeric_code, c.name, COUNT(b.swift_code)
+        FROM countries c
+        LEFT JOIN banks b ON b.country_iso2_code = c.iso2
+        GROUP BY c.iso2, c.iso3, c.numeric_code, c.name
+        ORDER BY c.iso2
+    `
+	rows, err := repo.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		country := new(model.Country)
+		if err := rows.Scan(&country.ISO2, &country.ISO3, &country.Numeric, &country.Name, &country.SwiftCodeCount); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		countries = append(countries, country)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return countries, nil
+}
+
+// Computes statistics of the SWIFT codes of a country, or of all codes when countryISO2 is empty
+func (repo *MySQLSwiftRepository) Stats(ctx context.Context, countryISO2 string) (stats *model.SwiftCodeStats, err error) {
+	ctx, done := trackQuery(ctx, "Stats", attribute.String("swift.country_iso2", countryISO2))
+	defer func() { done(1, err) }()
+
+	where := ""
+	var args []any
+	if countryISO2 != "" {
+		where = "WHERE country_iso2_code = ?"
+		args = append(args, countryISO2)
+	}
+
+	query := `
+        SELECT
+            COUNT(*),
+            COALESCE(SUM(CASE WHEN is_headquarter THEN 1 ELSE 0 END), 0),
+            COUNT(DISTINCT SUBSTR(swift_code, 1, 4)),
+            COUNT(DISTINCT town_name),
+            COUNT(DISTINCT country_iso2_code)
+        FROM banks
+        ` + where
+	stats = new(model.SwiftCodeStats)
+	err = repo.DB.QueryRowContext(ctx, query, args...).Scan(
+		&stats.SwiftCodes,
+		&stats.Headquarters,
+		&stats.Institutions,
+		&stats.Towns,
+		&stats.Countries,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	stats.Branches = stats.SwiftCodes - stats.Headquarters
+	if stats.SwiftCodes == 0 {
+		return stats, nil
+	}
+
+	// Selected as a plain column so that it is scanned as a time by every driver, unlike MAX(updated_at)
+	query = `SELECT updated_at FROM banks ` + where + ` ORDER BY updated_at DESC LIMIT 1`
+	var lastUpdated time.Time
+	if err = repo.DB.QueryRowContext(ctx, query, args...).Scan(&lastUpdated); err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	stats.LastUpdated = &lastUpdated
+
+	return stats, nil
+}
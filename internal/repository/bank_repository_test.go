@@ -3,7 +3,9 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/dodskygge/go_swift/internal/model"
 	"github.com/stretchr/testify/assert"
@@ -23,7 +25,25 @@ func setupTestDB(t *testing.T) *sql.DB {
             address TEXT NOT NULL,
             country_iso2_code TEXT NOT NULL,
             country_name TEXT NOT NULL,
-            is_headquarter BOOLEAN NOT NULL
+            is_headquarter BOOLEAN NOT NULL,
+            town_name TEXT,
+            updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            version INTEGER NOT NULL DEFAULT 1
+        )
+    `)
+	assert.NoError(t, err)
+
+	// Create the `audit_log` table
+	_, err = db.Exec(`
+        CREATE TABLE audit_log (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            actor TEXT NOT NULL,
+            action TEXT NOT NULL,
+            swift_code TEXT NOT NULL,
+            before_data TEXT,
+            after_data TEXT,
+            request_id TEXT NOT NULL DEFAULT ''
         )
     `)
 	assert.NoError(t, err)
@@ -51,6 +71,7 @@ func TestGetBySwiftCode(t *testing.T) {
 	assert.NotNil(t, entity)
 	assert.Equal(t, "Test Bank", entity.BankName)
 	assert.Equal(t, true, entity.IsHeadquarter)
+	assert.False(t, entity.UpdatedAt.IsZero())
 
 	// Test non-existent SWIFT code
 	entity, err = repo.GetBySwiftCode(context.Background(), "NONEXISTENT")
@@ -154,10 +175,17 @@ func TestDelete(t *testing.T) {
     `)
 	assert.NoError(t, err)
 
+	// Test Delete with a stale version
+	err = repo.Delete(context.Background(), "TESTUS33XXX", 2)
+	assert.ErrorIs(t, err, model.ErrVersionMismatch)
+
 	// Test Delete
-	err = repo.Delete(context.Background(), "TESTUS33XXX")
+	err = repo.Delete(context.Background(), "TESTUS33XXX", 1)
 	assert.NoError(t, err)
 
+	err = repo.Delete(context.Background(), "TESTUS33XXX", 0)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
 	// Verify data is deleted
 	row := db.QueryRow(`
         SELECT swift_code FROM banks WHERE swift_code = 'TESTUS33XXX'
@@ -165,3 +193,373 @@ func TestDelete(t *testing.T) {
 	err = row.Scan(new(string))
 	assert.Equal(t, sql.ErrNoRows, err)
 }
+
+// Unit test for CountByCountry
+func TestCountByCountry(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := &MySQLSwiftRepository{DB: db}
+
+	// Insert test data
+	_, err := db.Exec(`
+        INSERT INTO banks (swift_code, name, address, country_iso2_code, country_name, is_headquarter)
+        VALUES 
+            ('TESTUS33XXX', 'Test Bank HQ', '123 Main St', 'US', 'United States', TRUE),
+            ('TESTUS33ABC', 'Test Bank Branch', '456 Branch St', 'US', 'United States', FALSE),
+            ('TESTPLPWXXX', 'Test Bank PL', '1 Prosta St', 'PL', 'Poland', TRUE)
+    `)
+	assert.NoError(t, err)
+
+	// Test CountByCountry
+	counts, err := repo.CountByCountry(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"US": 2, "PL": 1}, counts)
+}
+
+// Unit test for GetByInstitution
+func TestGetByInstitution(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := &MySQLSwiftRepository{DB: db}
+
+	// Insert test data
+	_, err := db.Exec(`
+        INSERT INTO banks (swift_code, name, address, country_iso2_code, country_name, is_headquarter)
+        VALUES 
+            ('TESTUS33XXX', 'Test Bank HQ', '123 Main St', 'US', 'United States', TRUE),
+            ('TESTUS33ABC', 'Test Bank Branch', '456 Branch St', 'US', 'United States', FALSE),
+            ('TESTPLPWXXX', 'Test Bank PL', '1 Prosta St', 'PL', 'Poland', TRUE),
+            ('OTHRPLPWXXX', 'Other Bank', '2 Prosta St', 'PL', 'Poland', TRUE)
+    `)
+	assert.NoError(t, err)
+
+	// Test GetByInstitution
+	entities, err := repo.GetByInstitution(context.Background(), "TEST")
+	assert.NoError(t, err)
+	var codes []string
+	for _, entity := range entities {
+		codes = append(codes, entity.SwiftCode)
+	}
+	assert.Equal(t, []string{"TESTPLPWXXX", "TESTUS33ABC", "TESTUS33XXX"}, codes)
+
+	entities, err = repo.GetByInstitution(context.Background(), "NONE")
+	assert.NoError(t, err)
+	assert.Empty(t, entities)
+}
+
+// Unit test for ListCountries
+func TestListCountries(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := &MySQLSwiftRepository{DB: db}
+
+	// Insert test data
+	_, err := db.Exec(`
+        CREATE TABLE countries (
+            iso2 TEXT PRIMARY KEY,
+            iso3 TEXT NOT NULL,
+            numeric_code TEXT NOT NULL,
+            name TEXT NOT NULL
+        )
+    `)
+	assert.NoError(t, err)
+	_, err = db.Exec(`
+        INSERT INTO countries (iso2, iso3, numeric_code, name)
+        VALUES ('US', 'USA', '840', 'United States'), ('PL', 'POL', '616', 'Poland'), ('DE', 'DEU', '276', 'Germany')
+    `)
+	assert.NoError(t, err)
+	_, err = db.Exec(`
+        INSERT INTO banks (swift_code, name, address, country_iso2_code, country_name, is_headquarter)
+        VALUES 
+            ('TESTUS33XXX', 'Test Bank HQ', '123 Main St', 'US', 'United States', TRUE),
+            ('TESTUS33ABC', 'Test Bank Branch', '456 Branch St', 'US', 'United States', FALSE),
+            ('TESTPLPWXXX', 'Test Bank PL', '1 Prosta St', 'PL', 'Poland', TRUE)
+    `)
+	assert.NoError(t, err)
+
+	// Test ListCountries
+	countries, err := repo.ListCountries(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.Country{
+		{ISO2: "DE", ISO3: "DEU", Numeric: "276", Name: "Germany", SwiftCodeCount: 0},
+		{ISO2: "PL", ISO3: "POL", Numeric: "616", Name: "Poland", SwiftCodeCount: 1},
+		{ISO2: "US", ISO3: "USA", Numeric: "840", Name: "United States", SwiftCodeCount: 2},
+	}, countries)
+}
+
+// Unit test for Stats
+func TestStats(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := &MySQLSwiftRepository{DB: db}
+
+	// No codes yet
+	stats, err := repo.Stats(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Equal(t, &model.SwiftCodeStats{}, stats)
+
+	// Insert test data
+	_, err = db.Exec(`
+        INSERT INTO banks (swift_code, name, address, country_iso2_code, country_name, is_headquarter, town_name, updated_at)
+        VALUES 
+            ('TESTUS33XXX', 'Test Bank HQ', '123 Main St', 'US', 'United States', TRUE, 'NEW YORK', '2025-01-01 10:00:00'),
+            ('TESTUS33ABC', 'Test Bank Branch', '456 Branch St', 'US', 'United States', FALSE, 'BOSTON', '2025-03-01 10:00:00'),
+            ('OTHRUS44XXX', 'Other Bank', '1 Other St', 'US', 'United States', TRUE, 'NEW YORK', '2025-02-01 10:00:00'),
+            ('TESTPLPWXXX', 'Test Bank PL', '1 Prosta St', 'PL', 'Poland', TRUE, NULL, '2025-04-01 10:00:00')
+    `)
+	assert.NoError(t, err)
+
+	// Test Stats for one country
+	stats, err = repo.Stats(context.Background(), "US")
+	assert.NoError(t, err)
+	lastUpdated := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, &model.SwiftCodeStats{
+		Countries:    1,
+		SwiftCodes:   3,
+		Headquarters: 2,
+		Branches:     1,
+		Institutions: 2,
+		Towns:        2,
+		LastUpdated:  &lastUpdated,
+	}, stats)
+
+	// Test Stats for all countries
+	stats, err = repo.Stats(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stats.Countries)
+	assert.Equal(t, 4, stats.SwiftCodes)
+	assert.Equal(t, 3, stats.Headquarters)
+	assert.Equal(t, 2, stats.Institutions)
+	assert.Equal(t, 2, stats.Towns)
+	assert.Equal(t, time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC), *stats.LastUpdated)
+}
+
+// Unit test for Update
+func TestUpdate(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := &MySQLSwiftRepository{DB: db}
+
+	_, err := db.Exec(`
+        INSERT INTO banks (swift_code, name, address, country_iso2_code, country_name, is_headquarter)
+        VALUES ('TESTUS33XXX', 'Test Bank', '123 Main St', 'US', 'United States', TRUE)
+    `)
+	assert.NoError(t, err)
+
+	update := &model.SwiftEntity{
+		SwiftCode:   "TESTUS33XXX",
+		BankName:    "Renamed Bank",
+		Address:     "1 New St",
+		CountryISO2: "US",
+		CountryName: "UNITED STATES",
+	}
+	assert.NoError(t, repo.Update(context.Background(), update, 1))
+
+	entity, err := repo.GetBySwiftCode(context.Background(), "TESTUS33XXX")
+	assert.NoError(t, err)
+	assert.Equal(t, "Renamed Bank", entity.BankName)
+	assert.Equal(t, int64(2), entity.Version)
+
+	// The second writer based its change on version 1
+	update.BankName = "Lost Update"
+	assert.ErrorIs(t, repo.Update(context.Background(), update, 1), model.ErrVersionMismatch)
+
+	update.SwiftCode = "NONEXISTENT"
+	assert.ErrorIs(t, repo.Update(context.Background(), update, 0), model.ErrNotFound)
+}
+
+// Unit test for GetBySwiftCodes
+func TestGetBySwiftCodes(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := &MySQLSwiftRepository{DB: db}
+
+	_, err := db.Exec(`
+        INSERT INTO banks (swift_code, name, address, country_iso2_code, country_name, is_headquarter)
+        VALUES
+            ('TESTUS33XXX', 'Test Bank HQ', '123 Main St', 'US', 'United States', TRUE),
+            ('TESTUS33ABC', 'Test Bank Branch', '456 Branch St', 'US', 'United States', FALSE),
+            ('TESTPLPWXXX', 'Test Bank PL', '1 Prosta', 'PL', 'Poland', TRUE)
+    `)
+	assert.NoError(t, err)
+
+	entities, err := repo.GetBySwiftCodes(context.Background(), []string{"TESTUS33ABC", "TESTPLPWXXX", "MISSINGXXXX"})
+	assert.NoError(t, err)
+	assert.Len(t, entities, 2)
+
+	entities, err = repo.GetBySwiftCodes(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, entities)
+}
+
+// Unit test for GetBranchesByHqSwiftCodes
+func TestGetBranchesByHqSwiftCodes(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := &MySQLSwiftRepository{DB: db}
+
+	_, err := db.Exec(`
+        INSERT INTO banks (swift_code, name, address, country_iso2_code, country_name, is_headquarter)
+        VALUES
+            ('TESTUS33XXX', 'Test Bank HQ', '123 Main St', 'US', 'United States', TRUE),
+            ('TESTUS33ABC', 'Test Bank Branch', '456 Branch St', 'US', 'United States', FALSE),
+            ('TESTPLPWXXX', 'Test Bank PL', '1 Prosta', 'PL', 'Poland', TRUE),
+            ('TESTPLPWKRK', 'Test Bank PL Branch', '2 Krakowska', 'PL', 'Poland', FALSE),
+            ('OTHRPLPWABC', 'Other Bank Branch', '3 Długa', 'PL', 'Poland', FALSE)
+    `)
+	assert.NoError(t, err)
+
+	entities, err := repo.GetBranchesByHqSwiftCodes(context.Background(), []string{"TESTUS33", "TESTPLPW"})
+	assert.NoError(t, err)
+	if assert.Len(t, entities, 2) {
+		assert.Equal(t, "TESTPLPWKRK", entities[0].SwiftCode)
+		assert.Equal(t, "TESTUS33ABC", entities[1].SwiftCode)
+	}
+
+	entities, err = repo.GetBranchesByHqSwiftCodes(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, entities)
+}
+
+// Unit test for BulkWrite
+func TestBulkWrite(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := &MySQLSwiftRepository{DB: db}
+	ctx := context.Background()
+
+	_, err := db.Exec(`
+        INSERT INTO banks (swift_code, name, address, country_iso2_code, country_name, is_headquarter)
+        VALUES ('TESTUS33XXX', 'Test Bank', '123 Main St', 'US', 'United States', TRUE)
+    `)
+	assert.NoError(t, err)
+
+	entities := []*model.SwiftEntity{
+		{SwiftCode: "TESTPLPWXXX", BankName: "Test Bank PL", Address: "1 Prosta", CountryISO2: "PL", CountryName: "POLAND", IsHeadquarter: true},
+		{SwiftCode: "TESTUS33XXX", BankName: "Renamed Bank", Address: "1 New St", CountryISO2: "US", CountryName: "UNITED STATES", IsHeadquarter: true},
+	}
+
+	// All-or-nothing without upsert fails on the existing code and writes nothing
+	results, committed, err := repo.BulkWrite(ctx, entities, false, true)
+	assert.NoError(t, err)
+	assert.False(t, committed)
+	assert.Equal(t, model.BulkStatusNotApplied, results[0].Status)
+	assert.Equal(t, model.BulkStatusFailed, results[1].Status)
+	assert.Equal(t, model.ErrAlreadyExists.Error(), results[1].Error)
+	entity, err := repo.GetBySwiftCode(ctx, "TESTPLPWXXX")
+	assert.NoError(t, err)
+	assert.Nil(t, entity)
+
+	// Best-effort commits the new code
+	results, committed, err = repo.BulkWrite(ctx, entities, false, false)
+	assert.NoError(t, err)
+	assert.True(t, committed)
+	assert.Equal(t, model.BulkStatusCreated, results[0].Status)
+	assert.Equal(t, model.BulkStatusFailed, results[1].Status)
+	entity, err = repo.GetBySwiftCode(ctx, "TESTPLPWXXX")
+	assert.NoError(t, err)
+	assert.NotNil(t, entity)
+
+	// Upsert updates existing codes
+	results, committed, err = repo.BulkWrite(ctx, entities[1:], true, true)
+	assert.NoError(t, err)
+	assert.True(t, committed)
+	assert.Equal(t, model.BulkStatusUpdated, results[0].Status)
+	entity, err = repo.GetBySwiftCode(ctx, "TESTUS33XXX")
+	assert.NoError(t, err)
+	assert.Equal(t, "Renamed Bank", entity.BankName)
+	assert.Equal(t, int64(2), entity.Version)
+}
+
+// Unit test for ForEach
+func TestForEach(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := &MySQLSwiftRepository{DB: db}
+
+	_, err := db.Exec(`
+        INSERT INTO banks (swift_code, name, address, country_iso2_code, country_name, is_headquarter)
+        VALUES
+            ('TESTUS33XXX', 'Test Bank HQ', '123 Main St', 'US', 'United States', TRUE),
+            ('TESTUS33ABC', 'Test Bank Branch', '456 Branch St', 'US', 'United States', FALSE),
+            ('TESTPLPWXXX', 'Test Bank PL', '1 Prosta', 'PL', 'Poland', TRUE)
+    `)
+	assert.NoError(t, err)
+
+	collect := func(filter model.SwiftCodeFilter) []string {
+		var codes []string
+		err := repo.ForEach(context.Background(), filter, func(entity *model.SwiftEntity) error {
+			codes = append(codes, entity.SwiftCode)
+			return nil
+		})
+		assert.NoError(t, err)
+		return codes
+	}
+
+	assert.Equal(t, []string{"TESTPLPWXXX", "TESTUS33ABC", "TESTUS33XXX"}, collect(model.SwiftCodeFilter{}))
+	assert.Equal(t, []string{"TESTUS33ABC", "TESTUS33XXX"}, collect(model.SwiftCodeFilter{CountryISO2: "US"}))
+	hq := true
+	assert.Equal(t, []string{"TESTUS33XXX"}, collect(model.SwiftCodeFilter{CountryISO2: "US", IsHeadquarter: &hq}))
+
+	// An error from the callback stops the iteration
+	stop := errors.New("stop")
+	calls := 0
+	err = repo.ForEach(context.Background(), model.SwiftCodeFilter{}, func(*model.SwiftEntity) error {
+		calls++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+}
+
+// Unit test for Search
+func TestSearch(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := &MySQLSwiftRepository{DB: db}
+
+	_, err := db.Exec(`
+        INSERT INTO banks (swift_code, name, address, country_iso2_code, country_name, is_headquarter)
+        VALUES
+            ('TESTUS33XXX', 'Test Bank HQ', '123 Main St', 'US', 'United States', TRUE),
+            ('TESTUS33ABC', 'Test Bank Branch', '456 Branch St', 'US', 'United States', FALSE),
+            ('OTHRPLPWXXX', 'Other 100% Bank', '1 Prosta', 'PL', 'Poland', TRUE)
+    `)
+	assert.NoError(t, err)
+
+	search := func(search model.SwiftCodeSearch) []string {
+		entities, err := repo.Search(context.Background(), search)
+		assert.NoError(t, err)
+		var codes []string
+		for _, entity := range entities {
+			codes = append(codes, entity.SwiftCode)
+		}
+		return codes
+	}
+
+	// Code prefix or part of the bank name
+	assert.Equal(t, []string{"TESTUS33ABC", "TESTUS33XXX"}, search(model.SwiftCodeSearch{Query: "test", Limit: 10}))
+	assert.Equal(t, []string{"OTHRPLPWXXX", "TESTUS33ABC", "TESTUS33XXX"}, search(model.SwiftCodeSearch{Query: "bank", Limit: 10}))
+	assert.Equal(t, []string{"TESTUS33ABC"}, search(model.SwiftCodeSearch{Query: "branch", Limit: 10}))
+
+	// Wildcards match literally
+	assert.Equal(t, []string{"OTHRPLPWXXX"}, search(model.SwiftCodeSearch{Query: "100%", Limit: 10}))
+	assert.Empty(t, search(model.SwiftCodeSearch{Query: "_", Limit: 10}))
+
+	// Filters and paging
+	hq := true
+	assert.Equal(t, []string{"OTHRPLPWXXX", "TESTUS33XXX"}, search(model.SwiftCodeSearch{Filter: model.SwiftCodeFilter{IsHeadquarter: &hq}, Limit: 10}))
+	assert.Equal(t, []string{"TESTUS33ABC", "TESTUS33XXX"}, search(model.SwiftCodeSearch{Filter: model.SwiftCodeFilter{CountryISO2: "US"}, Limit: 10}))
+	assert.Equal(t, []string{"OTHRPLPWXXX"}, search(model.SwiftCodeSearch{Query: "bank", Limit: 1}))
+	assert.Equal(t, []string{"TESTUS33ABC"}, search(model.SwiftCodeSearch{Query: "bank", After: "OTHRPLPWXXX", Limit: 1}))
+}
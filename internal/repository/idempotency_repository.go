@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dodskygge/go_swift/internal/model"
+)
+
+type MySQLIdempotencyRepository struct {
+	DB *sql.DB
+}
+
+// Reserves an idempotency key for a new request; when the key was used before,
+// the stored record is returned instead and nothing is inserted. A reservation of the same request
+// still without a response and created before staleBefore, left by a crashed process, is taken over
+func (repo *MySQLIdempotencyRepository) Reserve(ctx context.Context, record *model.IdempotencyRecord, staleBefore time.Time) (existing *model.IdempotencyRecord, err error) {
+	ctx, done := trackQuery(ctx, "ReserveIdempotencyKey")
+	defer func() { done(1, err) }()
+
+	query := `
+        INSERT INTO idempotency_keys (principal, idempotency_key, request_hash, created_at)
+        VALUES (?, ?, ?, ?)
+    `
+	_, insertErr := repo.DB.ExecContext(ctx, query, record.Principal, record.Key, record.RequestHash, record.CreatedAt)
+	if insertErr == nil {
+		return nil, nil
+	}
+
+	// The insert violates the unique key when the key is already reserved
+	takeover := `
+        UPDATE idempotency_keys
+        SET created_at = ?
+        WHERE principal = ? AND idempotency_key = ? AND request_hash = ? AND status_code = 0 AND created_at < ?
+    `
+	result, err := repo.DB.ExecContext(ctx, takeover, record.CreatedAt, record.Principal, record.Key, record.RequestHash, staleBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute update query: %w", err)
+	}
+	taken, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if taken > 0 {
+		return nil, nil
+	}
+
+	existing, err = repo.get(ctx, record.Principal, record.Key)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("failed to execute insert query: %w", insertErr)
+	}
+	return existing, nil
+}
+
+// Retrieves the record of a key
+func (repo *MySQLIdempotencyRepository) get(ctx context.Context, principal, key string) (*model.IdempotencyRecord, error) {
+	query := `
+        SELECT principal, idempotency_key, request_hash, status_code, content_type, response_body, created_at
+        FROM idempotency_keys
+        WHERE principal = ? AND idempotency_key = ?
+    `
+	record := new(model.IdempotencyRecord)
+	err := repo.DB.QueryRowContext(ctx, query, principal, key).Scan(
+		&record.Principal,
+		&record.Key,
+		&record.RequestHash,
+		&record.StatusCode,
+		&record.ContentType,
+		&record.ResponseBody,
+		&record.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read idempotency key: %w", err)
+	}
+	return record, nil
+}
+
+// Stores the response produced for a reserved key
+func (repo *MySQLIdempotencyRepository) Complete(ctx context.Context, record *model.IdempotencyRecord) (err error) {
+	ctx, done := trackQuery(ctx, "CompleteIdempotencyKey")
+	defer func() { done(1, err) }()
+
+	query := `
+        UPDATE idempotency_keys
+        SET status_code = ?, content_type = ?, response_body = ?
+        WHERE principal = ? AND idempotency_key = ?
+    `
+	_, err = repo.DB.ExecContext(ctx, query,
+		record.StatusCode,
+		record.ContentType,
+		record.ResponseBody,
+		record.Principal,
+		record.Key,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to execute update query: %w", err)
+	}
+
+	return nil
+}
+
+// Drops a reservation so the request can be retried, used when it failed without a stored response
+func (repo *MySQLIdempotencyRepository) Release(ctx context.Context, principal, key string) (err error) {
+	ctx, done := trackQuery(ctx, "ReleaseIdempotencyKey")
+	defer func() { done(1, err) }()
+
+	query := `
+        DELETE FROM idempotency_keys
+        WHERE principal = ? AND idempotency_key = ? AND status_code = 0
+    `
+	if _, err = repo.DB.ExecContext(ctx, query, principal, key); err != nil {
+		return fmt.Errorf("failed to execute delete query: %w", err)
+	}
+
+	return nil
+}
+
+// Deletes keys created before the given time and returns how many were removed
+func (repo *MySQLIdempotencyRepository) DeleteExpired(ctx context.Context, before time.Time) (deleted int64, err error) {
+	ctx, done := trackQuery(ctx, "DeleteExpiredIdempotencyKeys")
+	defer func() { done(int(deleted), err) }()
+
+	result, err := repo.DB.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE created_at < ?`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute delete query: %w", err)
+	}
+
+	deleted, err = result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// Helper function to set up the idempotency_keys table
+func setupIdempotencyTable(t *testing.T, db *sql.DB) {
+	_, err := db.Exec(`
+        CREATE TABLE idempotency_keys (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            principal TEXT NOT NULL,
+            idempotency_key TEXT NOT NULL,
+            request_hash TEXT NOT NULL,
+            status_code INTEGER NOT NULL DEFAULT 0,
+            content_type TEXT NOT NULL DEFAULT '',
+            response_body BLOB,
+            created_at DATETIME NOT NULL,
+            UNIQUE (principal, idempotency_key)
+        )
+    `)
+	assert.NoError(t, err)
+}
+
+// Unit test for the idempotency key lifecycle
+func TestIdempotencyRepository(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	setupIdempotencyTable(t, db)
+
+	repo := &MySQLIdempotencyRepository{DB: db}
+	ctx := context.Background()
+	createdAt := time.Date(2025, 4, 23, 15, 30, 0, 0, time.UTC)
+	staleBefore := createdAt.Add(-time.Minute)
+
+	record := &model.IdempotencyRecord{Principal: "apikey:1", Key: "onboarding-42", RequestHash: "abc", CreatedAt: createdAt}
+
+	// Test Reserve of a new key
+	existing, err := repo.Reserve(ctx, record, staleBefore)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	// The same key is in progress until completed
+	existing, err = repo.Reserve(ctx, record, staleBefore)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, existing.StatusCode)
+
+	// A reservation older than the lock timeout is taken over by the same request only
+	retry := &model.IdempotencyRecord{Principal: "apikey:1", Key: "onboarding-42", RequestHash: "def", CreatedAt: createdAt.Add(2 * time.Minute)}
+	existing, err = repo.Reserve(ctx, retry, createdAt.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", existing.RequestHash)
+
+	retry.RequestHash = "abc"
+	existing, err = repo.Reserve(ctx, retry, createdAt.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	// The reservation is held again by the retry
+	existing, err = repo.Reserve(ctx, record, createdAt.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, existing.StatusCode)
+	assert.True(t, existing.CreatedAt.Equal(retry.CreatedAt))
+
+	// Keys are scoped per principal
+	existing, err = repo.Reserve(ctx, &model.IdempotencyRecord{Principal: "apikey:2", Key: "onboarding-42", RequestHash: "abc", CreatedAt: createdAt}, staleBefore)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	// Test Complete
+	record.StatusCode = 201
+	record.ContentType = "application/json"
+	record.ResponseBody = []byte(`{"message":"SWIFT code created successfully"}`)
+	assert.NoError(t, repo.Complete(ctx, record))
+
+	existing, err = repo.Reserve(ctx, record, staleBefore)
+	assert.NoError(t, err)
+	assert.Equal(t, 201, existing.StatusCode)
+	assert.Equal(t, record.ResponseBody, existing.ResponseBody)
+	assert.Equal(t, "abc", existing.RequestHash)
+
+	// Completed keys are not released
+	assert.NoError(t, repo.Release(ctx, "apikey:1", "onboarding-42"))
+	existing, err = repo.Reserve(ctx, record, staleBefore)
+	assert.NoError(t, err)
+	assert.NotNil(t, existing)
+
+	// Test Release of a reservation
+	assert.NoError(t, repo.Release(ctx, "apikey:2", "onboarding-42"))
+
+	// Test DeleteExpired
+	deleted, err := repo.DeleteExpired(ctx, retry.CreatedAt.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
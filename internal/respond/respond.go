@@ -0,0 +1,114 @@
+// Package respond renders response bodies in the media type negotiated from the Accept header.
+package respond
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dodskygge/go_swift/internal/model"
+)
+
+// NotAcceptableMessage is the error message for requests accepting none of the offered types.
+var NotAcceptableMessage = "Not acceptable, supported types are " + strings.Join(offeredTypes, ", ")
+
+// Media types responses can be rendered in, in order of preference when the client accepts several equally
+var offeredTypes = []string{"application/json", "application/xml", "text/xml"}
+
+// Negotiate returns the offered media type preferred by the Accept header of r, or false when none is
+// acceptable. A missing Accept header accepts JSON.
+func Negotiate(r *http.Request) (string, bool) {
+	accept := r.Header.Get("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return offeredTypes[0], true
+	}
+
+	best, bestQ := "", 0.0
+	for _, offer := range offeredTypes {
+		if q := acceptQuality(accept, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best, best != ""
+}
+
+// Returns the quality the Accept header assigns to mediaType, taken from its most specific matching range
+func acceptQuality(accept, mediaType string) float64 {
+	typ, _, _ := strings.Cut(mediaType, "/")
+
+	q, specificity := 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		mediaRange, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		var s int
+		switch {
+		case mediaRange == mediaType:
+			s = 2
+		case mediaRange == typ+"/*":
+			s = 1
+		case mediaRange == "*/*":
+			s = 0
+		default:
+			continue
+		}
+		if s <= specificity {
+			continue
+		}
+
+		specificity, q = s, 1
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil && parsed >= 0 && parsed <= 1 {
+				q = parsed
+			}
+		}
+	}
+	return q
+}
+
+// Encode encodes body in the given media type, application/json or an XML type, followed by a newline.
+func Encode(mediaType string, body any) ([]byte, error) {
+	if mediaType == "application/json" {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+
+	data, err := xml.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// Error writes model.ErrorResponse with the given status, as XML when the client prefers it and as JSON
+// otherwise, so that handlers and the middleware answering requests before them send errors alike.
+func Error(w http.ResponseWriter, r *http.Request, status int, message string) {
+	mediaType, ok := Negotiate(r)
+	if !ok {
+		mediaType = offeredTypes[0]
+	}
+
+	data, err := Encode(mediaType, model.ErrorResponse{Message: message})
+	if err != nil {
+		http.Error(w, message, status)
+		return
+	}
+
+	h := w.Header()
+	// Drop validators set for the representation that could not be sent
+	h.Del("ETag")
+	h.Del("Last-Modified")
+	h.Add("Vary", "Accept")
+	h.Set("Content-Type", mediaType)
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	w.Write(data)
+}
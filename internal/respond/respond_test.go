@@ -0,0 +1,58 @@
+package respond
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Unit test for Negotiate
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept    string
+		mediaType string
+		ok        bool
+	}{
+		{"", "application/json", true},
+		{"*/*", "application/json", true},
+		{"application/xml", "application/xml", true},
+		{"text/xml", "text/xml", true},
+		{"text/*", "text/xml", true},
+		{"application/xml, application/json;q=0.9", "application/xml", true},
+		{"application/xml;q=0.5, application/json", "application/json", true},
+		{"application/json;q=0, */*", "application/xml", true},
+		{"text/html, application/xhtml+xml", "", false},
+		{"application/json;q=0", "", false},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", tt.accept)
+		mediaType, ok := Negotiate(req)
+		assert.Equal(t, tt.ok, ok, tt.accept)
+		assert.Equal(t, tt.mediaType, mediaType, tt.accept)
+	}
+}
+
+// Unit test for Error following the Accept header
+func TestError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "application/xml")
+	rec := httptest.NewRecorder()
+	rec.Header().Set("ETag", `"1-abc"`)
+	Error(rec, req, http.StatusTooManyRequests, "Too many requests")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "application/xml", rec.Header().Get("Content-Type"))
+	assert.Empty(t, rec.Header().Get("ETag"))
+	assert.Equal(t, xml.Header+"<error><message>Too many requests</message></error>\n", rec.Body.String())
+
+	// Clients accepting none of the types get JSON
+	req.Header.Set("Accept", "text/html")
+	rec = httptest.NewRecorder()
+	Error(rec, req, http.StatusUnauthorized, "Unauthorized")
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `{"error":"Unauthorized"}`+"\n", rec.Body.String())
+}
@@ -2,33 +2,143 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/dodskygge/go_swift/internal/metrics"
 	"github.com/dodskygge/go_swift/internal/model"
+	"github.com/dodskygge/go_swift/internal/tracing"
+	"github.com/dodskygge/go_swift/pkg/bic"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // SwiftCodeRepository defines the interface for repository operations related to SWIFT codes.
 type SwiftCodeRepository interface {
 	GetBySwiftCode(ctx context.Context, code string) (*model.SwiftEntity, error)
 	GetBranchesByHqSwiftCode(ctx context.Context, hqCode string) ([]*model.SwiftEntity, error)
+	GetBranchesByHqSwiftCodes(ctx context.Context, hqCodes []string) ([]*model.SwiftEntity, error)
 	GetByCountry(ctx context.Context, countryISO2 string) ([]*model.SwiftEntity, error)
+	GetByInstitution(ctx context.Context, bic4 string) ([]*model.SwiftEntity, error)
+	GetBySwiftCodes(ctx context.Context, swiftCodes []string) ([]*model.SwiftEntity, error)
+	Search(ctx context.Context, search model.SwiftCodeSearch) ([]*model.SwiftEntity, error)
+	ForEach(ctx context.Context, filter model.SwiftCodeFilter, fn func(*model.SwiftEntity) error) error
 	Create(ctx context.Context, swift *model.SwiftEntity) error
-	Delete(ctx context.Context, swiftCode string) error
+	BulkWrite(ctx context.Context, entities []*model.SwiftEntity, upsert, atomic bool) ([]model.BulkItemResult, bool, error)
+	// Update and Delete return model.ErrNotFound or model.ErrVersionMismatch when the code
+	// does not exist or is not at expectedVersion; an expectedVersion of 0 matches any version.
+	Update(ctx context.Context, swift *model.SwiftEntity, expectedVersion int64) error
+	Delete(ctx context.Context, swiftCode string, expectedVersion int64) error
+	ListCountries(ctx context.Context) ([]*model.Country, error)
+	// Stats computes statistics of the codes of a country, or of all codes when countryISO2 is empty.
+	Stats(ctx context.Context, countryISO2 string) (*model.SwiftCodeStats, error)
+	// Create, BulkWrite, Update and Delete record every change in the audit log read by GetAuditLog.
+	GetAuditLog(ctx context.Context, query model.AuditQuery) ([]model.AuditEntry, error)
 }
 
 // SwiftCodeService provides business logic for SWIFT code operations.
 type SwiftCodeService struct {
-	repo SwiftCodeRepository
+	repo         SwiftCodeRepository
+	codeCache    *ttlCache[*model.SwiftCodeResponse]
+	countryCache *ttlCache[*model.SwiftCodesByCountryResponse]
+	maxBatchSize int
+
+	// ISO 3166 reference countries by ISO2 code, loaded on first use
+	countriesMu sync.Mutex
+	countries   map[string]*model.Country
+}
+
+// DefaultMaxBatchSize is the number of codes a batch lookup accepts unless changed with WithMaxBatchSize.
+const DefaultMaxBatchSize = 1000
+
+// Number of codes a search returns when no limit is given, and the most it returns.
+const (
+	DefaultSearchLimit = 50
+	MaxSearchLimit     = 500
+)
+
+var (
+	// ErrBatchTooLarge is returned when a batch lookup or bulk write contains more codes than allowed.
+	ErrBatchTooLarge = errors.New("too many SWIFT codes in batch")
+	// ErrInvalidBulkMode is returned for a bulk write mode other than all-or-nothing and best-effort.
+	ErrInvalidBulkMode = errors.New("invalid bulk mode")
+)
+
+// Error for request data failing validation, matched by model.ErrInvalidInput
+type validationError string
+
+func (e validationError) Error() string { return string(e) }
+
+func (e validationError) Is(target error) bool { return target == model.ErrInvalidInput }
+
+// Option configures optional behavior of a SwiftCodeService.
+type Option func(*SwiftCodeService)
+
+// WithCacheTTL enables caching of lookup responses for the given duration.
+// Cached entries are dropped whenever a SWIFT code is created or deleted.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(s *SwiftCodeService) {
+		if ttl > 0 {
+			s.codeCache = newTTLCache[*model.SwiftCodeResponse](ttl)
+			s.countryCache = newTTLCache[*model.SwiftCodesByCountryResponse](ttl)
+		}
+	}
+}
+
+// WithMaxBatchSize limits the number of codes accepted by a batch lookup.
+func WithMaxBatchSize(n int) Option {
+	return func(s *SwiftCodeService) {
+		if n > 0 {
+			s.maxBatchSize = n
+		}
+	}
+}
+
+// MaxBatchSize returns the number of codes a batch lookup or bulk write accepts.
+func (s *SwiftCodeService) MaxBatchSize() int {
+	return s.maxBatchSize
 }
 
 // NewSwiftCodeService initializes a new SwiftCodeService with the given repository.
-func NewSwiftCodeService(repo SwiftCodeRepository) *SwiftCodeService {
-	return &SwiftCodeService{repo: repo}
+func NewSwiftCodeService(repo SwiftCodeRepository, opts ...Option) *SwiftCodeService {
+	s := &SwiftCodeService{repo: repo, maxBatchSize: DefaultMaxBatchSize}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Starts a span for a service operation
+func startSpan(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, "SwiftCodeService."+method, trace.WithAttributes(attrs...))
+}
+
+// Drops all cached lookup responses after a data change
+func (s *SwiftCodeService) invalidateCache() {
+	if s.codeCache != nil {
+		s.codeCache.clear()
+		s.countryCache.clear()
+	}
 }
 
 // GetSwiftCodeDetails retrieves details for a specific SWIFT code, including branches if it's a headquarters.
-func (s *SwiftCodeService) GetSwiftCodeDetails(ctx context.Context, swiftCode string) (*model.SwiftCodeResponse, error) {
+func (s *SwiftCodeService) GetSwiftCodeDetails(ctx context.Context, swiftCode string) (_ *model.SwiftCodeResponse, err error) {
+	ctx, span := startSpan(ctx, "GetSwiftCodeDetails", attribute.String("swift.code", swiftCode))
+	defer func() { tracing.End(span, err) }()
+
+	if s.codeCache != nil {
+		cached, ok := s.codeCache.get(swiftCode)
+		metrics.ObserveCache("swift_code", ok)
+		span.SetAttributes(attribute.Bool("cache.hit", ok))
+		if ok {
+			return cached, nil
+		}
+	}
+
 	entity, err := s.repo.GetBySwiftCode(ctx, swiftCode)
 	if err != nil {
 		return nil, err
@@ -49,6 +159,8 @@ func (s *SwiftCodeService) GetSwiftCodeDetails(ctx context.Context, swiftCode st
 		IsHeadquarter: entity.IsHeadquarter,
 		SwiftCode:     entity.SwiftCode,
 		Branches:      []model.SwiftCodeBranch{},
+		LastModified:  entity.UpdatedAt,
+		Version:       entity.Version,
 	}
 
 	// If HQ, retrieve branches
@@ -76,11 +188,80 @@ func (s *SwiftCodeService) GetSwiftCodeDetails(ctx context.Context, swiftCode st
 		}
 	}
 
+	span.SetAttributes(
+		attribute.String("swift.country_iso2", response.CountryISO2),
+		attribute.Int("swift.branches", len(response.Branches)),
+	)
+	if s.codeCache != nil {
+		s.codeCache.set(swiftCode, response)
+	}
 	return response, nil
 }
 
+// GetBranchesByHeadquarters retrieves the branches of many headquarters SWIFT codes, keyed by headquarters code,
+// querying the repository once per batch of codes instead of once per headquarters.
+func (s *SwiftCodeService) GetBranchesByHeadquarters(ctx context.Context, hqCodes []string) (_ map[string][]model.SwiftCodeBranch, err error) {
+	ctx, span := startSpan(ctx, "GetBranchesByHeadquarters", attribute.Int("swift.headquarters", len(hqCodes)))
+	defer func() { tracing.End(span, err) }()
+
+	branches := make(map[string][]model.SwiftCodeBranch, len(hqCodes))
+	// Headquarters code per 8-character prefix shared with its branches
+	byPrefix := make(map[string]string, len(hqCodes))
+	var prefixes []string
+	for _, code := range hqCodes {
+		if len(code) < 8 {
+			continue
+		}
+		branches[code] = []model.SwiftCodeBranch{}
+		if _, ok := byPrefix[code[:8]]; !ok {
+			byPrefix[code[:8]] = code
+			prefixes = append(prefixes, code[:8])
+		}
+	}
+
+	count := 0
+	for start := 0; start < len(prefixes); start += s.maxBatchSize {
+		end := min(start+s.maxBatchSize, len(prefixes))
+		entities, err := s.repo.GetBranchesByHqSwiftCodes(ctx, prefixes[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range entities {
+			hqCode, ok := byPrefix[b.SwiftCode[:8]]
+			if !ok {
+				continue
+			}
+			branches[hqCode] = append(branches[hqCode], model.SwiftCodeBranch{
+				Address:       b.Address,
+				BankName:      b.BankName,
+				CountryISO2:   strings.ToUpper(b.CountryISO2),
+				IsHeadquarter: b.IsHeadquarter,
+				SwiftCode:     b.SwiftCode,
+			})
+			count++
+		}
+	}
+
+	span.SetAttributes(attribute.Int("swift.branches", count))
+	return branches, nil
+}
+
 // GetSwiftCodesByCountry retrieves all SWIFT codes for a specific country.
-func (s *SwiftCodeService) GetSwiftCodesByCountry(ctx context.Context, countryISO2 string) (*model.SwiftCodesByCountryResponse, error) {
+func (s *SwiftCodeService) GetSwiftCodesByCountry(ctx context.Context, countryISO2 string) (_ *model.SwiftCodesByCountryResponse, err error) {
+	ctx, span := startSpan(ctx, "GetSwiftCodesByCountry", attribute.String("swift.country_iso2", countryISO2))
+	defer func() { tracing.End(span, err) }()
+
+	// Normalize country codes to uppercase, the key of cached responses
+	countryISO2 = strings.ToUpper(countryISO2)
+	if s.countryCache != nil {
+		cached, ok := s.countryCache.get(countryISO2)
+		metrics.ObserveCache("country", ok)
+		span.SetAttributes(attribute.Bool("cache.hit", ok))
+		if ok {
+			return cached, nil
+		}
+	}
+
 	entities, err := s.repo.GetByCountry(ctx, countryISO2)
 	if err != nil {
 		return nil, err
@@ -89,8 +270,7 @@ func (s *SwiftCodeService) GetSwiftCodesByCountry(ctx context.Context, countryIS
 		return nil, nil
 	}
 
-	// Normalize country codes and names to uppercase
-	countryISO2 = strings.ToUpper(countryISO2)
+	// Normalize country names to uppercase
 	countryName := strings.ToUpper(entities[0].CountryName)
 
 	response := &model.SwiftCodesByCountryResponse{
@@ -110,59 +290,578 @@ func (s *SwiftCodeService) GetSwiftCodesByCountry(ctx context.Context, countryIS
 		response.SwiftCodes = append(response.SwiftCodes, swiftCode)
 	}
 
+	span.SetAttributes(attribute.Int("swift.codes", len(response.SwiftCodes)))
+	if s.countryCache != nil {
+		s.countryCache.set(countryISO2, response)
+	}
 	return response, nil
 }
 
-// CreateSwiftCode validates and creates a new SWIFT code entry in the database.
-func (s *SwiftCodeService) CreateSwiftCode(ctx context.Context, req model.CreateSwiftCodeRequest) error {
+// GetInstitution retrieves the headquarters and branches sharing an institution code across countries,
+// grouped by country. It returns nil when the institution has no SWIFT codes.
+func (s *SwiftCodeService) GetInstitution(ctx context.Context, bic4 string) (_ *model.InstitutionResponse, err error) {
+	ctx, span := startSpan(ctx, "GetInstitution", attribute.String("swift.institution", bic4))
+	defer func() { tracing.End(span, err) }()
+
+	bic4 = strings.ToUpper(bic4)
+	if !bic.ValidInstitution(bic4) {
+		return nil, validationError("invalid institution code: must be 4 letters")
+	}
+
+	entities, err := s.repo.GetByInstitution(ctx, bic4)
+	if err != nil {
+		return nil, err
+	}
+	if len(entities) == 0 {
+		return nil, nil
+	}
+
+	response := &model.InstitutionResponse{InstitutionCode: bic4, Countries: []model.InstitutionCountry{}}
+	for _, entity := range entities {
+		countryISO2 := strings.ToUpper(entity.CountryISO2)
+		// Entities are ordered by country, so a new country starts a new group
+		last := len(response.Countries) - 1
+		if last < 0 || response.Countries[last].CountryISO2 != countryISO2 {
+			response.Countries = append(response.Countries, model.InstitutionCountry{
+				CountryISO2: countryISO2,
+				CountryName: strings.ToUpper(entity.CountryName),
+				SwiftCodes:  []model.SwiftCodeMinimalResponse{},
+			})
+			last++
+		}
+		response.Countries[last].SwiftCodes = append(response.Countries[last].SwiftCodes, model.SwiftCodeMinimalResponse{
+			Address:       entity.Address,
+			BankName:      entity.BankName,
+			CountryISO2:   countryISO2,
+			IsHeadquarter: entity.IsHeadquarter,
+			SwiftCode:     entity.SwiftCode,
+		})
+	}
+
+	span.SetAttributes(
+		attribute.Int("swift.codes", len(entities)),
+		attribute.Int("swift.countries", len(response.Countries)),
+	)
+	return response, nil
+}
+
+// BatchLookup resolves many SWIFT codes with a single query. Codes are trimmed and uppercased;
+// malformed codes are reported as invalid and duplicates are looked up once.
+func (s *SwiftCodeService) BatchLookup(ctx context.Context, swiftCodes []string) (_ *model.BatchLookupResponse, err error) {
+	ctx, span := startSpan(ctx, "BatchLookup", attribute.Int("swift.codes_requested", len(swiftCodes)))
+	defer func() { tracing.End(span, err) }()
+
+	if len(swiftCodes) > s.maxBatchSize {
+		return nil, fmt.Errorf("%w: %d codes, at most %d are allowed", ErrBatchTooLarge, len(swiftCodes), s.maxBatchSize)
+	}
+
+	response := &model.BatchLookupResponse{
+		Found:    []model.SwiftCodeResponse{},
+		NotFound: []string{},
+		Invalid:  []string{},
+	}
+
+	var codes []string
+	seen := make(map[string]bool, len(swiftCodes))
+	for _, code := range swiftCodes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		if !bic.Valid(code) {
+			response.Invalid = append(response.Invalid, code)
+			continue
+		}
+		codes = append(codes, code)
+	}
+
+	entities, err := s.repo.GetBySwiftCodes(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+	byCode := make(map[string]*model.SwiftEntity, len(entities))
+	for _, entity := range entities {
+		byCode[entity.SwiftCode] = entity
+	}
+
+	for _, code := range codes {
+		entity, ok := byCode[code]
+		if !ok {
+			response.NotFound = append(response.NotFound, code)
+			continue
+		}
+		response.Found = append(response.Found, model.SwiftCodeResponse{
+			Address:       entity.Address,
+			BankName:      entity.BankName,
+			CountryISO2:   strings.ToUpper(entity.CountryISO2),
+			CountryName:   strings.ToUpper(entity.CountryName),
+			IsHeadquarter: entity.IsHeadquarter,
+			SwiftCode:     entity.SwiftCode,
+		})
+	}
+
+	span.SetAttributes(
+		attribute.Int("swift.codes_found", len(response.Found)),
+		attribute.Int("swift.codes_invalid", len(response.Invalid)),
+	)
+	return response, nil
+}
+
+// SearchSwiftCodes returns one page of the SWIFT codes matching the search, without branches, and the
+// code to pass as search.After for the next page, which is empty on the last page.
+func (s *SwiftCodeService) SearchSwiftCodes(ctx context.Context, search model.SwiftCodeSearch) (_ []model.SwiftCodeResponse, next string, err error) {
+	ctx, span := startSpan(ctx, "SearchSwiftCodes",
+		attribute.String("swift.query", search.Query),
+		attribute.String("swift.country_iso2", search.Filter.CountryISO2),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	if search.Limit <= 0 {
+		search.Limit = DefaultSearchLimit
+	}
+	if search.Limit > MaxSearchLimit {
+		search.Limit = MaxSearchLimit
+	}
+	limit := search.Limit
+	search.Query = strings.TrimSpace(search.Query)
+	search.Filter.CountryISO2 = strings.ToUpper(search.Filter.CountryISO2)
+
+	// One extra code tells whether there is a next page
+	search.Limit++
+	entities, err := s.repo.Search(ctx, search)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(entities) > limit {
+		entities = entities[:limit]
+		next = entities[limit-1].SwiftCode
+	}
+
+	results := make([]model.SwiftCodeResponse, 0, len(entities))
+	for _, entity := range entities {
+		results = append(results, model.SwiftCodeResponse{
+			Address:       entity.Address,
+			BankName:      entity.BankName,
+			CountryISO2:   strings.ToUpper(entity.CountryISO2),
+			CountryName:   strings.ToUpper(entity.CountryName),
+			IsHeadquarter: entity.IsHeadquarter,
+			SwiftCode:     entity.SwiftCode,
+			LastModified:  entity.UpdatedAt,
+			Version:       entity.Version,
+		})
+	}
+
+	span.SetAttributes(attribute.Int("swift.codes_found", len(results)))
+	return results, next, nil
+}
+
+// ExportSwiftCodes streams the SWIFT codes matching the filter to fn, ordered by code, in the record
+// format accepted by CreateSwiftCode and BulkWrite so that exports can be imported again.
+func (s *SwiftCodeService) ExportSwiftCodes(ctx context.Context, filter model.SwiftCodeFilter, fn func(model.CreateSwiftCodeRequest) error) (err error) {
+	ctx, span := startSpan(ctx, "ExportSwiftCodes", attribute.String("swift.country_iso2", filter.CountryISO2))
+	defer func() { tracing.End(span, err) }()
+
+	filter.CountryISO2 = strings.ToUpper(filter.CountryISO2)
+
+	count := 0
+	err = s.repo.ForEach(ctx, filter, func(entity *model.SwiftEntity) error {
+		count++
+		return fn(model.CreateSwiftCodeRequest{
+			Address:       entity.Address,
+			BankName:      entity.BankName,
+			CountryISO2:   strings.ToUpper(entity.CountryISO2),
+			CountryName:   strings.ToUpper(entity.CountryName),
+			IsHeadquarter: entity.IsHeadquarter,
+			SwiftCode:     entity.SwiftCode,
+		})
+	})
+
+	span.SetAttributes(attribute.Int("swift.codes", count))
+	return err
+}
+
+// ValidateSwiftCode checks a record against the rules applied when it is created, returning an error
+// matching model.ErrInvalidInput when it breaks one. The country is checked against the reference countries
+// only when the record is written.
+func ValidateSwiftCode(req model.CreateSwiftCodeRequest) error {
+	_, err := newSwiftEntity(req)
+	return err
+}
+
+// Validates a create request and builds the entity to store
+func newSwiftEntity(req model.CreateSwiftCodeRequest) (*model.SwiftEntity, error) {
 	// Validate input data.
 	if len(req.SwiftCode) < 8 {
-		return fmt.Errorf("invalid SWIFT code: must be at least 8 characters")
+		return nil, validationError("invalid SWIFT code: must be at least 8 characters")
 	}
-	if req.CountryISO2 == "" || req.CountryName == "" {
-		return fmt.Errorf("countryISO2 and countryName cannot be empty")
+	if req.CountryISO2 == "" {
+		return nil, validationError("countryISO2 cannot be empty")
 	}
 	if req.BankName == "" || req.Address == "" {
-		return fmt.Errorf("bankName and address cannot be empty")
+		return nil, validationError("bankName and address cannot be empty")
 	}
 
 	// Check if the SWIFT code is valid
 	isHQ := len(req.SwiftCode) == 11 && req.SwiftCode[8:] == "XXX"
 	if isHQ != req.IsHeadquarter {
-		return fmt.Errorf("SWIFT code does not match the provided isHeadquarter value")
+		return nil, validationError("SWIFT code does not match the provided isHeadquarter value")
 	}
 
 	// Create a new SWIFT entity.
-	entity := &model.SwiftEntity{
+	return &model.SwiftEntity{
 		SwiftCode:     req.SwiftCode,
 		BankName:      req.BankName,
 		Address:       req.Address,
 		CountryISO2:   strings.ToUpper(req.CountryISO2),
 		CountryName:   strings.ToUpper(req.CountryName),
 		IsHeadquarter: req.IsHeadquarter,
+	}, nil
+}
+
+// BulkWrite validates every record with the create rules and writes the valid ones in one transaction,
+// updating existing codes when upsert is set. In all-or-nothing mode nothing is written unless every
+// record is valid and succeeds; in best-effort mode the failed records are reported and the rest written.
+func (s *SwiftCodeService) BulkWrite(ctx context.Context, reqs []model.CreateSwiftCodeRequest, mode string, upsert bool) (_ *model.BulkWriteResponse, err error) {
+	ctx, span := startSpan(ctx, "BulkWrite",
+		attribute.Int("swift.codes_requested", len(reqs)),
+		attribute.String("bulk.mode", mode),
+		attribute.Bool("bulk.upsert", upsert),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	if mode != model.BulkAllOrNothing && mode != model.BulkBestEffort {
+		return nil, fmt.Errorf("%w %q: must be %s or %s", ErrInvalidBulkMode, mode, model.BulkAllOrNothing, model.BulkBestEffort)
+	}
+	if len(reqs) > s.maxBatchSize {
+		return nil, fmt.Errorf("%w: %d codes, at most %d are allowed", ErrBatchTooLarge, len(reqs), s.maxBatchSize)
+	}
+
+	countries, err := s.referenceCountries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.BulkWriteResponse{Mode: mode, Items: make([]model.BulkItemResult, len(reqs))}
+
+	// Validate every record first; indexes maps entities back to their position in the request
+	var entities []*model.SwiftEntity
+	var indexes []int
+	seen := make(map[string]bool, len(reqs))
+	for i, req := range reqs {
+		response.Items[i] = model.BulkItemResult{Index: i, SwiftCode: req.SwiftCode}
+
+		entity, err := newSwiftEntity(req)
+		if err == nil {
+			entity.CountryName, err = countryName(countries, entity.CountryISO2, entity.CountryName)
+		}
+		if err == nil && seen[entity.SwiftCode] {
+			err = fmt.Errorf("duplicate SWIFT code in request")
+		}
+		if err != nil {
+			response.Items[i].Status = model.BulkStatusFailed
+			response.Items[i].Error = err.Error()
+			continue
+		}
+		seen[entity.SwiftCode] = true
+		entities = append(entities, entity)
+		indexes = append(indexes, i)
+	}
+
+	atomic := mode == model.BulkAllOrNothing
+	invalid := len(entities) < len(reqs)
+	if atomic && invalid {
+		for _, i := range indexes {
+			response.Items[i].Status = model.BulkStatusNotApplied
+		}
+	} else if len(entities) > 0 {
+		results, committed, err := s.repo.BulkWrite(ctx, entities, upsert, atomic)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write SWIFT codes: %w", err)
+		}
+		for j, result := range results {
+			result.Index = indexes[j]
+			response.Items[indexes[j]] = result
+		}
+		response.Committed = committed
+	} else {
+		// Nothing to write
+		response.Committed = true
+	}
+
+	for _, item := range response.Items {
+		switch item.Status {
+		case model.BulkStatusCreated:
+			response.Created++
+		case model.BulkStatusUpdated:
+			response.Updated++
+		case model.BulkStatusFailed:
+			response.Failed++
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Bool("bulk.committed", response.Committed),
+		attribute.Int("bulk.failed", response.Failed),
+	)
+	if response.Created+response.Updated > 0 {
+		s.invalidateCache()
+		slog.InfoContext(ctx, "SWIFT codes written in bulk", "mode", mode, "created", response.Created, "updated", response.Updated, "failed", response.Failed)
+	}
+	return response, nil
+}
+
+// CreateSwiftCode validates and creates a new SWIFT code entry in the database.
+func (s *SwiftCodeService) CreateSwiftCode(ctx context.Context, req model.CreateSwiftCodeRequest) (err error) {
+	ctx, span := startSpan(ctx, "CreateSwiftCode",
+		attribute.String("swift.code", req.SwiftCode),
+		attribute.String("swift.country_iso2", req.CountryISO2),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	entity, err := newSwiftEntity(req)
+	if err != nil {
+		return err
+	}
+	if err := s.checkCountry(ctx, entity); err != nil {
+		return err
 	}
 
 	// Save the entity in the database.
-	err := s.repo.Create(ctx, entity)
+	err = s.repo.Create(ctx, entity)
 	if err != nil {
 		return fmt.Errorf("failed to create SWIFT code: %w", err)
 	}
 
+	s.invalidateCache()
+	slog.InfoContext(ctx, "SWIFT code created", "swift_code", entity.SwiftCode, "country_iso2", entity.CountryISO2)
+	return nil
+}
+
+// UpdateSwiftCode validates and updates a SWIFT code entry, provided it is still at expectedVersion.
+// An expectedVersion of 0 updates the current version.
+func (s *SwiftCodeService) UpdateSwiftCode(ctx context.Context, swiftCode string, req model.UpdateSwiftCodeRequest, expectedVersion int64) (err error) {
+	ctx, span := startSpan(ctx, "UpdateSwiftCode",
+		attribute.String("swift.code", swiftCode),
+		attribute.Int64("swift.expected_version", expectedVersion),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	// Validate input data.
+	if len(swiftCode) < 8 {
+		return validationError("invalid SWIFT code: must be at least 8 characters")
+	}
+	if req.CountryISO2 == "" {
+		return validationError("countryISO2 cannot be empty")
+	}
+	if req.BankName == "" || req.Address == "" {
+		return validationError("bankName and address cannot be empty")
+	}
+
+	entity := &model.SwiftEntity{
+		SwiftCode:   swiftCode,
+		BankName:    req.BankName,
+		Address:     req.Address,
+		CountryISO2: strings.ToUpper(req.CountryISO2),
+		CountryName: strings.ToUpper(req.CountryName),
+	}
+	if err := s.checkCountry(ctx, entity); err != nil {
+		return err
+	}
+
+	err = s.repo.Update(ctx, entity, expectedVersion)
+	if err != nil {
+		return fmt.Errorf("failed to update SWIFT code: %w", err)
+	}
+
+	s.invalidateCache()
+	slog.InfoContext(ctx, "SWIFT code updated", "swift_code", swiftCode)
 	return nil
 }
 
-// DeleteSwiftCode deletes a SWIFT code entry from the database.
-func (s *SwiftCodeService) DeleteSwiftCode(ctx context.Context, swiftCode string) error {
+// DeleteSwiftCode deletes a SWIFT code entry from the database, provided it is still at expectedVersion.
+// An expectedVersion of 0 deletes the current version.
+func (s *SwiftCodeService) DeleteSwiftCode(ctx context.Context, swiftCode string, expectedVersion int64) (err error) {
+	ctx, span := startSpan(ctx, "DeleteSwiftCode",
+		attribute.String("swift.code", swiftCode),
+		attribute.Int64("swift.expected_version", expectedVersion),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	// Validate the SWIFT code.
 	if len(swiftCode) < 8 {
-		return fmt.Errorf("invalid SWIFT code: must be at least 8 characters")
+		return validationError("invalid SWIFT code: must be at least 8 characters")
 	}
 
 	// Delete the SWIFT code from the database.
-	err := s.repo.Delete(ctx, swiftCode)
+	err = s.repo.Delete(ctx, swiftCode, expectedVersion)
 	if err != nil {
 		return fmt.Errorf("failed to delete SWIFT code: %w", err)
 	}
 
+	s.invalidateCache()
+	slog.InfoContext(ctx, "SWIFT code deleted", "swift_code", swiftCode)
 	return nil
 }
+
+// ListCountries returns the ISO 3166 reference countries with the number of SWIFT codes of each.
+func (s *SwiftCodeService) ListCountries(ctx context.Context) (_ *model.CountriesResponse, err error) {
+	ctx, span := startSpan(ctx, "ListCountries")
+	defer func() { tracing.End(span, err) }()
+
+	countries, err := s.repo.ListCountries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.CountriesResponse{Countries: make([]model.Country, 0, len(countries))}
+	for _, country := range countries {
+		response.Countries = append(response.Countries, *country)
+	}
+
+	span.SetAttributes(attribute.Int("swift.countries", len(response.Countries)))
+	return response, nil
+}
+
+// Returns the reference countries by ISO2 code; they only change with migrations, so they are loaded once
+func (s *SwiftCodeService) referenceCountries(ctx context.Context) (map[string]*model.Country, error) {
+	s.countriesMu.Lock()
+	defer s.countriesMu.Unlock()
+
+	if s.countries != nil {
+		return s.countries, nil
+	}
+
+	countries, err := s.repo.ListCountries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load countries: %w", err)
+	}
+	byCode := make(map[string]*model.Country, len(countries))
+	for _, country := range countries {
+		byCode[country.ISO2] = country
+	}
+	s.countries = byCode
+	return byCode, nil
+}
+
+// Checks the country of an entity against the reference countries, setting its name when omitted
+func (s *SwiftCodeService) checkCountry(ctx context.Context, entity *model.SwiftEntity) error {
+	countries, err := s.referenceCountries(ctx)
+	if err != nil {
+		return err
+	}
+	entity.CountryName, err = countryName(countries, entity.CountryISO2, entity.CountryName)
+	return err
+}
+
+// Returns the uppercase reference name of a country, rejecting unknown codes and names that differ from the
+// reference name other than in case
+func countryName(countries map[string]*model.Country, countryISO2, name string) (string, error) {
+	country, ok := countries[countryISO2]
+	if !ok {
+		return "", validationError(fmt.Sprintf("countryISO2 %q is not an ISO 3166 country code", countryISO2))
+	}
+	if name != "" && !strings.EqualFold(name, country.Name) {
+		return "", validationError(fmt.Sprintf("countryName %q does not match countryISO2 %s (%s)", name, countryISO2, country.Name))
+	}
+	return strings.ToUpper(country.Name), nil
+}
+
+// GetStats returns statistics of all SWIFT codes.
+func (s *SwiftCodeService) GetStats(ctx context.Context) (_ *model.SwiftCodeStats, err error) {
+	ctx, span := startSpan(ctx, "GetStats")
+	defer func() { tracing.End(span, err) }()
+
+	return s.repo.Stats(ctx, "")
+}
+
+// GetCountryStats returns statistics of the SWIFT codes of a country, or model.ErrNotFound when the
+// country is not one of the reference countries.
+func (s *SwiftCodeService) GetCountryStats(ctx context.Context, countryISO2 string) (_ *model.SwiftCodeStats, err error) {
+	ctx, span := startSpan(ctx, "GetCountryStats", attribute.String("swift.country_iso2", countryISO2))
+	defer func() { tracing.End(span, err) }()
+
+	countryISO2 = strings.ToUpper(countryISO2)
+	countries, err := s.referenceCountries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	country, ok := countries[countryISO2]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+
+	stats, err := s.repo.Stats(ctx, countryISO2)
+	if err != nil {
+		return nil, err
+	}
+	stats.CountryISO2 = countryISO2
+	stats.CountryName = strings.ToUpper(country.Name)
+	stats.Countries = 0
+	return stats, nil
+}
+
+// QueryAuditLog returns one page of the audit log entries matching the query, oldest first. The limit defaults
+// to DefaultSearchLimit and is capped at MaxSearchLimit.
+func (s *SwiftCodeService) QueryAuditLog(ctx context.Context, query model.AuditQuery) (_ *model.AuditLogResponse, err error) {
+	ctx, span := startSpan(ctx, "QueryAuditLog",
+		attribute.String("swift.code", query.SwiftCode),
+		attribute.String("audit.actor", query.Actor),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	switch query.Action {
+	case "", model.AuditActionCreate, model.AuditActionUpdate, model.AuditActionDelete:
+	default:
+		return nil, validationError(fmt.Sprintf("action %q must be %s, %s or %s", query.Action,
+			model.AuditActionCreate, model.AuditActionUpdate, model.AuditActionDelete))
+	}
+	if !query.Since.IsZero() && !query.Until.IsZero() && query.Until.Before(query.Since) {
+		return nil, validationError("until must not be before since")
+	}
+	if query.Limit <= 0 {
+		query.Limit = DefaultSearchLimit
+	}
+	if query.Limit > MaxSearchLimit {
+		query.Limit = MaxSearchLimit
+	}
+	limit := query.Limit
+
+	// One extra entry tells whether there is a next page
+	query.Limit++
+	entries, err := s.repo.GetAuditLog(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	response := &model.AuditLogResponse{Entries: entries}
+	if len(entries) > limit {
+		response.Entries = entries[:limit]
+		response.Next = entries[limit-1].ID
+	}
+	if response.Entries == nil {
+		response.Entries = []model.AuditEntry{}
+	}
+
+	span.SetAttributes(attribute.Int("audit.entries", len(response.Entries)))
+	return response, nil
+}
+
+// GetSwiftCodeHistory returns one page of the changes of a SWIFT code, oldest first, including those of
+// deleted codes. It returns model.ErrNotFound when the code was never changed.
+func (s *SwiftCodeService) GetSwiftCodeHistory(ctx context.Context, swiftCode string, after int64, limit int) (_ *model.AuditLogResponse, err error) {
+	ctx, span := startSpan(ctx, "GetSwiftCodeHistory", attribute.String("swift.code", swiftCode))
+	defer func() { tracing.End(span, err) }()
+
+	if len(swiftCode) < 8 {
+		return nil, validationError("invalid SWIFT code: must be at least 8 characters")
+	}
+
+	response, err := s.QueryAuditLog(ctx, model.AuditQuery{SwiftCode: swiftCode, After: after, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Entries) == 0 && after == 0 {
+		return nil, model.ErrNotFound
+	}
+	return response, nil
+}
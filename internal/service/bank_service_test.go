@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/dodskygge/go_swift/internal/model"
 	"github.com/stretchr/testify/assert"
@@ -30,6 +31,14 @@ func (m *MockSwiftCodeRepository) GetBranchesByHqSwiftCode(ctx context.Context,
 	return args.Get(0).([]*model.SwiftEntity), args.Error(1)
 }
 
+func (m *MockSwiftCodeRepository) GetBranchesByHqSwiftCodes(ctx context.Context, hqCodes []string) ([]*model.SwiftEntity, error) {
+	args := m.Called(ctx, hqCodes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SwiftEntity), args.Error(1)
+}
+
 func (m *MockSwiftCodeRepository) GetByCountry(ctx context.Context, countryISO2 string) ([]*model.SwiftEntity, error) {
 	args := m.Called(ctx, countryISO2)
 	if args.Get(0) == nil {
@@ -38,16 +47,93 @@ func (m *MockSwiftCodeRepository) GetByCountry(ctx context.Context, countryISO2
 	return args.Get(0).([]*model.SwiftEntity), args.Error(1)
 }
 
+func (m *MockSwiftCodeRepository) GetBySwiftCodes(ctx context.Context, swiftCodes []string) ([]*model.SwiftEntity, error) {
+	args := m.Called(ctx, swiftCodes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SwiftEntity), args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) Search(ctx context.Context, search model.SwiftCodeSearch) ([]*model.SwiftEntity, error) {
+	args := m.Called(ctx, search)
+	entities, _ := args.Get(0).([]*model.SwiftEntity)
+	return entities, args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) ForEach(ctx context.Context, filter model.SwiftCodeFilter, fn func(*model.SwiftEntity) error) error {
+	args := m.Called(ctx, filter, fn)
+	if entities, ok := args.Get(0).([]*model.SwiftEntity); ok {
+		for _, entity := range entities {
+			if err := fn(entity); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
 func (m *MockSwiftCodeRepository) Create(ctx context.Context, swift *model.SwiftEntity) error {
 	args := m.Called(ctx, swift)
 	return args.Error(0)
 }
 
-func (m *MockSwiftCodeRepository) Delete(ctx context.Context, swiftCode string) error {
-	args := m.Called(ctx, swiftCode)
+func (m *MockSwiftCodeRepository) BulkWrite(ctx context.Context, entities []*model.SwiftEntity, upsert, atomic bool) ([]model.BulkItemResult, bool, error) {
+	args := m.Called(ctx, entities, upsert, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).([]model.BulkItemResult), args.Bool(1), args.Error(2)
+}
+
+func (m *MockSwiftCodeRepository) Update(ctx context.Context, swift *model.SwiftEntity, expectedVersion int64) error {
+	args := m.Called(ctx, swift, expectedVersion)
+	return args.Error(0)
+}
+
+func (m *MockSwiftCodeRepository) Delete(ctx context.Context, swiftCode string, expectedVersion int64) error {
+	args := m.Called(ctx, swiftCode, expectedVersion)
 	return args.Error(0)
 }
 
+func (m *MockSwiftCodeRepository) ListCountries(ctx context.Context) ([]*model.Country, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Country), args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) Stats(ctx context.Context, countryISO2 string) (*model.SwiftCodeStats, error) {
+	args := m.Called(ctx, countryISO2)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SwiftCodeStats), args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) GetAuditLog(ctx context.Context, query model.AuditQuery) ([]model.AuditEntry, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AuditEntry), args.Error(1)
+}
+
+func (m *MockSwiftCodeRepository) GetByInstitution(ctx context.Context, bic4 string) ([]*model.SwiftEntity, error) {
+	args := m.Called(ctx, bic4)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SwiftEntity), args.Error(1)
+}
+
+// Reference countries returned by the mock repository
+var testCountries = []*model.Country{
+	{ISO2: "PL", ISO3: "POL", Numeric: "616", Name: "Poland"},
+	{ISO2: "US", ISO3: "USA", Numeric: "840", Name: "United States"},
+}
+
 // Unit test for GetSwiftCodeDetails
 func TestGetSwiftCodeDetails(t *testing.T) {
 	mockRepo := new(MockSwiftCodeRepository)
@@ -61,6 +147,7 @@ func TestGetSwiftCodeDetails(t *testing.T) {
 		CountryISO2:   "US",
 		CountryName:   "United States",
 		IsHeadquarter: true,
+		UpdatedAt:     time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC),
 	}
 	mockBranches := []*model.SwiftEntity{
 		{
@@ -70,6 +157,7 @@ func TestGetSwiftCodeDetails(t *testing.T) {
 			CountryISO2:   "US",
 			CountryName:   "United States",
 			IsHeadquarter: false,
+			UpdatedAt:     time.Date(2025, 4, 2, 10, 0, 0, 0, time.UTC),
 		},
 	}
 
@@ -86,6 +174,7 @@ func TestGetSwiftCodeDetails(t *testing.T) {
 	assert.Equal(t, "Test Bank", result.BankName)
 	assert.Len(t, result.Branches, 1)
 	assert.Equal(t, "Test Branch", result.Branches[0].BankName)
+	assert.Equal(t, mockEntity.UpdatedAt, result.LastModified)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -138,8 +227,9 @@ func TestCreateSwiftCode(t *testing.T) {
 		IsHeadquarter: true,
 	}
 
-	// Define mock behavior
-	mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	// Define mock behavior; the reference countries are loaded once
+	mockRepo.On("ListCountries", mock.Anything).Return(testCountries, nil).Once()
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
 
 	// Call service
 	err := service.CreateSwiftCode(context.Background(), req)
@@ -147,6 +237,24 @@ func TestCreateSwiftCode(t *testing.T) {
 	// Assert results
 	assert.NoError(t, err)
 
+	// The country name is derived from the code when omitted
+	req.SwiftCode, req.CountryISO2, req.CountryName = "TESTPLPWXXX", "pl", ""
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(entity *model.SwiftEntity) bool {
+		return entity.CountryISO2 == "PL" && entity.CountryName == "POLAND"
+	})).Return(nil).Once()
+	assert.NoError(t, service.CreateSwiftCode(context.Background(), req))
+
+	// Unknown codes and names differing from the reference never reach the repository
+	req.CountryISO2 = "XX"
+	err = service.CreateSwiftCode(context.Background(), req)
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+	assert.EqualError(t, err, `countryISO2 "XX" is not an ISO 3166 country code`)
+
+	req.CountryISO2, req.CountryName = "PL", "Polska"
+	err = service.CreateSwiftCode(context.Background(), req)
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+	assert.EqualError(t, err, `countryName "POLSKA" does not match countryISO2 PL (Poland)`)
+
 	mockRepo.AssertExpectations(t)
 }
 
@@ -156,10 +264,10 @@ func TestDeleteSwiftCode(t *testing.T) {
 	service := NewSwiftCodeService(mockRepo)
 
 	// Define mock behavior
-	mockRepo.On("Delete", mock.Anything, "TESTUS33XXX").Return(nil)
+	mockRepo.On("Delete", mock.Anything, "TESTUS33XXX", int64(3)).Return(nil)
 
 	// Call service
-	err := service.DeleteSwiftCode(context.Background(), "TESTUS33XXX")
+	err := service.DeleteSwiftCode(context.Background(), "TESTUS33XXX", 3)
 
 	// Assert results
 	assert.NoError(t, err)
@@ -167,6 +275,37 @@ func TestDeleteSwiftCode(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+// Unit test for UpdateSwiftCode
+func TestUpdateSwiftCode(t *testing.T) {
+	mockRepo := new(MockSwiftCodeRepository)
+	service := NewSwiftCodeService(mockRepo)
+
+	req := model.UpdateSwiftCodeRequest{
+		BankName:    "Renamed Bank",
+		Address:     "1 New St",
+		CountryISO2: "us",
+		CountryName: "United States",
+	}
+	expectedEntity := &model.SwiftEntity{
+		SwiftCode:   "TESTUS33XXX",
+		BankName:    "Renamed Bank",
+		Address:     "1 New St",
+		CountryISO2: "US",
+		CountryName: "UNITED STATES",
+	}
+	mockRepo.On("ListCountries", mock.Anything).Return(testCountries, nil)
+	mockRepo.On("Update", mock.Anything, expectedEntity, int64(2)).Return(model.ErrVersionMismatch)
+
+	err := service.UpdateSwiftCode(context.Background(), "TESTUS33XXX", req, 2)
+	assert.ErrorIs(t, err, model.ErrVersionMismatch)
+
+	// Validation errors never reach the repository
+	req.BankName = ""
+	assert.Error(t, service.UpdateSwiftCode(context.Background(), "TESTUS33XXX", req, 2))
+
+	mockRepo.AssertExpectations(t)
+}
+
 // Unit test for CreateSwiftCode with validation error
 func TestCreateSwiftCodeValidationError(t *testing.T) {
 	mockRepo := new(MockSwiftCodeRepository)
@@ -189,3 +328,320 @@ func TestCreateSwiftCodeValidationError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid SWIFT code")
 }
+
+// Unit test for GetSwiftCodeDetails served from the cache
+func TestGetSwiftCodeDetailsCached(t *testing.T) {
+	mockRepo := new(MockSwiftCodeRepository)
+	service := NewSwiftCodeService(mockRepo, WithCacheTTL(time.Minute))
+
+	mockEntity := &model.SwiftEntity{
+		SwiftCode:     "TESTUS33ABC",
+		BankName:      "Test Branch",
+		Address:       "456 Branch St",
+		CountryISO2:   "US",
+		CountryName:   "United States",
+		IsHeadquarter: false,
+	}
+
+	// The repository must be queried only once while the entry is cached
+	mockRepo.On("GetBySwiftCode", mock.Anything, "TESTUS33ABC").Return(mockEntity, nil).Once()
+
+	first, err := service.GetSwiftCodeDetails(context.Background(), "TESTUS33ABC")
+	assert.NoError(t, err)
+	second, err := service.GetSwiftCodeDetails(context.Background(), "TESTUS33ABC")
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	// Deleting a code invalidates the cache
+	mockRepo.On("Delete", mock.Anything, "TESTUS33ABC", int64(0)).Return(nil)
+	assert.NoError(t, service.DeleteSwiftCode(context.Background(), "TESTUS33ABC", 0))
+
+	mockRepo.On("GetBySwiftCode", mock.Anything, "TESTUS33ABC").Return(nil, nil).Once()
+	result, err := service.GetSwiftCodeDetails(context.Background(), "TESTUS33ABC")
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// Unit test for GetSwiftCodesByCountry served from the cache whatever the case of the country code
+func TestGetSwiftCodesByCountryCached(t *testing.T) {
+	mockRepo := new(MockSwiftCodeRepository)
+	service := NewSwiftCodeService(mockRepo, WithCacheTTL(time.Minute))
+
+	mockRepo.On("GetByCountry", mock.Anything, "US").Return([]*model.SwiftEntity{
+		{SwiftCode: "TESTUS33XXX", BankName: "Test Bank", CountryISO2: "US", CountryName: "United States", IsHeadquarter: true},
+	}, nil).Once()
+
+	first, err := service.GetSwiftCodesByCountry(context.Background(), "us")
+	assert.NoError(t, err)
+	second, err := service.GetSwiftCodesByCountry(context.Background(), "us")
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+	third, err := service.GetSwiftCodesByCountry(context.Background(), "US")
+	assert.NoError(t, err)
+	assert.Equal(t, first, third)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// Unit test for BatchLookup
+func TestBatchLookup(t *testing.T) {
+	mockRepo := new(MockSwiftCodeRepository)
+	service := NewSwiftCodeService(mockRepo, WithMaxBatchSize(5))
+
+	mockRepo.On("GetBySwiftCodes", mock.Anything, []string{"TESTUS33XXX", "MISSPLPWXXX", "TESTUS33"}).Return([]*model.SwiftEntity{
+		{SwiftCode: "TESTUS33", BankName: "Test Bank", CountryISO2: "us", CountryName: "United States"},
+		{SwiftCode: "TESTUS33XXX", BankName: "Test Bank", CountryISO2: "us", CountryName: "United States", IsHeadquarter: true},
+	}, nil)
+
+	result, err := service.BatchLookup(context.Background(), []string{" testus33xxx", "MISSPLPWXXX", "TESTUS33XXX", "TEST1", "TESTUS33"})
+	assert.NoError(t, err)
+	assert.Len(t, result.Found, 2)
+	assert.Equal(t, "TESTUS33XXX", result.Found[0].SwiftCode)
+	assert.Equal(t, "UNITED STATES", result.Found[0].CountryName)
+	assert.Equal(t, []string{"MISSPLPWXXX"}, result.NotFound)
+	assert.Equal(t, []string{"TEST1"}, result.Invalid)
+
+	_, err = service.BatchLookup(context.Background(), make([]string, 6))
+	assert.ErrorIs(t, err, ErrBatchTooLarge)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// Unit test for GetBranchesByHeadquarters
+func TestGetBranchesByHeadquarters(t *testing.T) {
+	mockRepo := new(MockSwiftCodeRepository)
+	service := NewSwiftCodeService(mockRepo, WithMaxBatchSize(2))
+
+	// Headquarters are queried in batches of at most WithMaxBatchSize prefixes
+	mockRepo.On("GetBranchesByHqSwiftCodes", mock.Anything, []string{"TESTUS33", "TESTPLPW"}).Return([]*model.SwiftEntity{
+		{SwiftCode: "TESTPLPWKRK", BankName: "Test Bank PL", CountryISO2: "pl"},
+		{SwiftCode: "TESTUS33ABC", BankName: "Test Bank", CountryISO2: "us"},
+	}, nil).Once()
+	mockRepo.On("GetBranchesByHqSwiftCodes", mock.Anything, []string{"NONEDEFF"}).Return(nil, nil).Once()
+
+	result, err := service.GetBranchesByHeadquarters(context.Background(), []string{"TESTUS33XXX", "TESTPLPWXXX", "TESTUS33XXX", "NONEDEFFXXX"})
+	assert.NoError(t, err)
+	assert.Len(t, result, 3)
+	assert.Equal(t, []model.SwiftCodeBranch{{SwiftCode: "TESTUS33ABC", BankName: "Test Bank", CountryISO2: "US"}}, result["TESTUS33XXX"])
+	assert.Equal(t, "TESTPLPWKRK", result["TESTPLPWXXX"][0].SwiftCode)
+	assert.Empty(t, result["NONEDEFFXXX"])
+
+	mockRepo.AssertExpectations(t)
+}
+
+// Unit test for ValidateSwiftCode
+func TestValidateSwiftCode(t *testing.T) {
+	req := model.CreateSwiftCodeRequest{SwiftCode: "TESTUS33XXX", BankName: "Test Bank", Address: "123 Main St", CountryISO2: "US", CountryName: "United States", IsHeadquarter: true}
+	assert.NoError(t, ValidateSwiftCode(req))
+
+	req.IsHeadquarter = false
+	assert.ErrorIs(t, ValidateSwiftCode(req), model.ErrInvalidInput)
+}
+
+// Unit test for GetInstitution
+func TestGetInstitution(t *testing.T) {
+	mockRepo := new(MockSwiftCodeRepository)
+	service := NewSwiftCodeService(mockRepo)
+
+	mockRepo.On("GetByInstitution", mock.Anything, "TPEO").Return([]*model.SwiftEntity{
+		{SwiftCode: "TPEOLULLXXX", BankName: "Pekao LU", Address: "1 Rue", CountryISO2: "lu", CountryName: "Luxembourg", IsHeadquarter: true},
+		{SwiftCode: "TPEOPLPWABC", BankName: "Pekao TFI", Address: "2 Prosta", CountryISO2: "PL", CountryName: "Poland"},
+		{SwiftCode: "TPEOPLPWXXX", BankName: "Pekao TFI", Address: "1 Prosta", CountryISO2: "PL", CountryName: "Poland", IsHeadquarter: true},
+	}, nil)
+	mockRepo.On("GetByInstitution", mock.Anything, "NONE").Return(nil, nil)
+
+	result, err := service.GetInstitution(context.Background(), "tpeo")
+	assert.NoError(t, err)
+	assert.Equal(t, "TPEO", result.InstitutionCode)
+	if assert.Len(t, result.Countries, 2) {
+		assert.Equal(t, "LU", result.Countries[0].CountryISO2)
+		assert.Equal(t, "LUXEMBOURG", result.Countries[0].CountryName)
+		assert.Len(t, result.Countries[0].SwiftCodes, 1)
+		assert.Equal(t, "PL", result.Countries[1].CountryISO2)
+		assert.Equal(t, "TPEOPLPWXXX", result.Countries[1].SwiftCodes[1].SwiftCode)
+	}
+
+	result, err = service.GetInstitution(context.Background(), "NONE")
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+
+	_, err = service.GetInstitution(context.Background(), "TP%")
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// Unit test for QueryAuditLog and GetSwiftCodeHistory
+func TestQueryAuditLog(t *testing.T) {
+	mockRepo := new(MockSwiftCodeRepository)
+	service := NewSwiftCodeService(mockRepo)
+
+	entries := []model.AuditEntry{
+		{ID: 1, Action: model.AuditActionCreate, SwiftCode: "TESTUS33XXX"},
+		{ID: 2, Action: model.AuditActionUpdate, SwiftCode: "TESTUS33XXX"},
+		{ID: 3, Action: model.AuditActionDelete, SwiftCode: "TESTUS33XXX"},
+	}
+
+	// The limit is asked for with one extra entry telling there is a next page
+	mockRepo.On("GetAuditLog", mock.Anything, model.AuditQuery{Actor: "apikey:1", Limit: 3}).Return(entries, nil)
+	result, err := service.QueryAuditLog(context.Background(), model.AuditQuery{Actor: "apikey:1", Limit: 2})
+	assert.NoError(t, err)
+	assert.Len(t, result.Entries, 2)
+	assert.Equal(t, int64(2), result.Next)
+
+	mockRepo.On("GetAuditLog", mock.Anything, model.AuditQuery{SwiftCode: "TESTUS33XXX", Limit: DefaultSearchLimit + 1}).Return(entries, nil)
+	result, err = service.GetSwiftCodeHistory(context.Background(), "TESTUS33XXX", 0, 0)
+	assert.NoError(t, err)
+	assert.Len(t, result.Entries, 3)
+	assert.Zero(t, result.Next)
+
+	// A code without changes has no history
+	mockRepo.On("GetAuditLog", mock.Anything, model.AuditQuery{SwiftCode: "NONEUS33XXX", Limit: MaxSearchLimit + 1}).Return(nil, nil)
+	_, err = service.GetSwiftCodeHistory(context.Background(), "NONEUS33XXX", 0, 1000)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	_, err = service.QueryAuditLog(context.Background(), model.AuditQuery{Action: "read"})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+	_, err = service.QueryAuditLog(context.Background(), model.AuditQuery{Since: time.Now(), Until: time.Now().Add(-time.Hour)})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+	_, err = service.GetSwiftCodeHistory(context.Background(), "SHORT", 0, 0)
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// Unit test for GetStats and GetCountryStats
+func TestGetStats(t *testing.T) {
+	mockRepo := new(MockSwiftCodeRepository)
+	service := NewSwiftCodeService(mockRepo)
+
+	mockRepo.On("Stats", mock.Anything, "").Return(&model.SwiftCodeStats{Countries: 2, SwiftCodes: 3}, nil)
+	stats, err := service.GetStats(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stats.Countries)
+
+	// Country statistics carry the reference name of the country
+	mockRepo.On("ListCountries", mock.Anything).Return(testCountries, nil).Once()
+	mockRepo.On("Stats", mock.Anything, "PL").Return(&model.SwiftCodeStats{Countries: 1, SwiftCodes: 1, Headquarters: 1}, nil)
+	stats, err = service.GetCountryStats(context.Background(), "pl")
+	assert.NoError(t, err)
+	assert.Equal(t, &model.SwiftCodeStats{CountryISO2: "PL", CountryName: "POLAND", SwiftCodes: 1, Headquarters: 1}, stats)
+
+	_, err = service.GetCountryStats(context.Background(), "XX")
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// Unit test for BulkWrite
+func TestBulkWrite(t *testing.T) {
+	mockRepo := new(MockSwiftCodeRepository)
+	service := NewSwiftCodeService(mockRepo)
+
+	reqs := []model.CreateSwiftCodeRequest{
+		{SwiftCode: "TESTUS33XXX", BankName: "Test Bank", Address: "123 Main St", CountryISO2: "us", CountryName: "United States", IsHeadquarter: true},
+		{SwiftCode: "TESTUS33ABC", BankName: "", Address: "456 Branch St", CountryISO2: "US", CountryName: "United States"},
+		{SwiftCode: "TESTPLPWXXX", BankName: "Test Bank PL", Address: "1 Prosta", CountryISO2: "PL", CountryName: "Poland", IsHeadquarter: true},
+		{SwiftCode: "TESTUS33XXX", BankName: "Test Bank", Address: "123 Main St", CountryISO2: "US", CountryName: "United States", IsHeadquarter: true},
+		{SwiftCode: "TESTXX33XXX", BankName: "Test Bank XX", Address: "1 Nowhere", CountryISO2: "XX", IsHeadquarter: true},
+	}
+	mockRepo.On("ListCountries", mock.Anything).Return(testCountries, nil).Once()
+
+	// All-or-nothing with invalid records never reaches the repository
+	result, err := service.BulkWrite(context.Background(), reqs, model.BulkAllOrNothing, false)
+	assert.NoError(t, err)
+	assert.False(t, result.Committed)
+	assert.Equal(t, 3, result.Failed)
+	assert.Equal(t, model.BulkStatusNotApplied, result.Items[0].Status)
+	assert.Equal(t, model.BulkStatusFailed, result.Items[1].Status)
+	assert.Equal(t, "duplicate SWIFT code in request", result.Items[3].Error)
+	assert.Equal(t, `countryISO2 "XX" is not an ISO 3166 country code`, result.Items[4].Error)
+
+	// Best-effort writes the valid records and maps results back to request positions
+	mockRepo.On("BulkWrite", mock.Anything, mock.MatchedBy(func(entities []*model.SwiftEntity) bool {
+		return len(entities) == 2 && entities[0].CountryISO2 == "US" && entities[1].SwiftCode == "TESTPLPWXXX"
+	}), true, false).Return([]model.BulkItemResult{
+		{Index: 0, SwiftCode: "TESTUS33XXX", Status: model.BulkStatusUpdated},
+		{Index: 1, SwiftCode: "TESTPLPWXXX", Status: model.BulkStatusCreated},
+	}, true, nil)
+
+	result, err = service.BulkWrite(context.Background(), reqs, model.BulkBestEffort, true)
+	assert.NoError(t, err)
+	assert.True(t, result.Committed)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 3, result.Failed)
+	assert.Equal(t, 2, result.Items[2].Index)
+	assert.Equal(t, model.BulkStatusCreated, result.Items[2].Status)
+
+	_, err = service.BulkWrite(context.Background(), reqs, "sometimes", false)
+	assert.ErrorIs(t, err, ErrInvalidBulkMode)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// Unit test for ExportSwiftCodes
+func TestExportSwiftCodes(t *testing.T) {
+	mockRepo := new(MockSwiftCodeRepository)
+	service := NewSwiftCodeService(mockRepo)
+
+	hq := true
+	mockRepo.On("ForEach", mock.Anything, model.SwiftCodeFilter{CountryISO2: "US", IsHeadquarter: &hq}, mock.Anything).Return([]*model.SwiftEntity{
+		{SwiftCode: "TESTUS33XXX", BankName: "Test Bank", Address: "123 Main St", CountryISO2: "US", CountryName: "United States", IsHeadquarter: true},
+	}, nil)
+
+	var records []model.CreateSwiftCodeRequest
+	err := service.ExportSwiftCodes(context.Background(), model.SwiftCodeFilter{CountryISO2: "us", IsHeadquarter: &hq}, func(record model.CreateSwiftCodeRequest) error {
+		records = append(records, record)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []model.CreateSwiftCodeRequest{
+		{SwiftCode: "TESTUS33XXX", BankName: "Test Bank", Address: "123 Main St", CountryISO2: "US", CountryName: "UNITED STATES", IsHeadquarter: true},
+	}, records)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// Unit test for SearchSwiftCodes paging
+func TestSearchSwiftCodes(t *testing.T) {
+	mockRepo := new(MockSwiftCodeRepository)
+	service := NewSwiftCodeService(mockRepo)
+
+	entities := []*model.SwiftEntity{
+		{SwiftCode: "TESTUS33ABC", BankName: "Test Bank", CountryISO2: "us", CountryName: "United States"},
+		{SwiftCode: "TESTUS33XXX", BankName: "Test Bank", CountryISO2: "us", CountryName: "United States", IsHeadquarter: true},
+	}
+	mockRepo.On("Search", mock.Anything, model.SwiftCodeSearch{Query: "test", Filter: model.SwiftCodeFilter{CountryISO2: "US"}, Limit: 2}).Return(entities, nil)
+	mockRepo.On("Search", mock.Anything, model.SwiftCodeSearch{Query: "test", After: "TESTUS33ABC", Limit: DefaultSearchLimit + 1}).Return(entities[1:], nil)
+
+	// A full page links to the next one
+	results, next, err := service.SearchSwiftCodes(context.Background(), model.SwiftCodeSearch{Query: " test ", Filter: model.SwiftCodeFilter{CountryISO2: "us"}, Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "TESTUS33ABC", results[0].SwiftCode)
+	assert.Equal(t, "US", results[0].CountryISO2)
+	assert.Equal(t, "TESTUS33ABC", next)
+
+	// The last page does not
+	results, next, err = service.SearchSwiftCodes(context.Background(), model.SwiftCodeSearch{Query: "test", After: next})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Empty(t, next)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// Unit test for validation errors matching model.ErrInvalidInput
+func TestValidationErrors(t *testing.T) {
+	service := NewSwiftCodeService(new(MockSwiftCodeRepository))
+
+	err := service.CreateSwiftCode(context.Background(), model.CreateSwiftCodeRequest{SwiftCode: "SHORT"})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+	assert.EqualError(t, err, "invalid SWIFT code: must be at least 8 characters")
+
+	err = service.DeleteSwiftCode(context.Background(), "SHORT", 0)
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+}
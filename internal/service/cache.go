@@ -0,0 +1,49 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// ttlCache is a small in-memory cache whose entries expire after a fixed duration.
+type ttlCache[V any] struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]cacheEntry[V]
+}
+
+type cacheEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+func newTTLCache[V any](ttl time.Duration) *ttlCache[V] {
+	return &ttlCache[V]{ttl: ttl, entries: make(map[string]cacheEntry[V])}
+}
+
+// Returns the cached value for key if it has not expired
+func (c *ttlCache[V]) get(key string) (V, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if !ok || time.Now().After(entry.expires) {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// Stores a value for key
+func (c *ttlCache[V]) set(key string, value V) {
+	c.mu.Lock()
+	c.entries[key] = cacheEntry[V]{value: value, expires: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+}
+
+// Removes all entries
+func (c *ttlCache[V]) clear() {
+	c.mu.Lock()
+	c.entries = make(map[string]cacheEntry[V])
+	c.mu.Unlock()
+}
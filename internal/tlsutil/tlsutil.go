@@ -0,0 +1,184 @@
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// reloadCheckInterval limits how often the certificate files are checked for changes.
+const reloadCheckInterval = 5 * time.Second
+
+// CertReloader serves a key pair from disk and reloads it when either file changes,
+// so renewed certificates are picked up without restarting the server.
+type CertReloader struct {
+	certFile string
+	keyFile  string
+	now      func() time.Time
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	lastCheck time.Time
+}
+
+// NewCertReloader loads the key pair and returns a reloader serving it.
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{certFile: certFile, keyFile: keyFile, now: time.Now}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate.
+// A failed reload keeps serving the previous certificate.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if now.Sub(r.lastCheck) >= reloadCheckInterval {
+		r.lastCheck = now
+		if modTime, err := r.latestModTime(); err == nil && modTime.After(r.modTime) {
+			if err := r.loadLocked(); err != nil {
+				slog.Error("failed to reload TLS certificate", "cert_file", r.certFile, "error", err)
+			} else {
+				slog.Info("TLS certificate reloaded", "cert_file", r.certFile)
+			}
+		}
+	}
+
+	return r.cert, nil
+}
+
+func (r *CertReloader) load() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.loadLocked()
+}
+
+// Loads the key pair; the caller holds the lock
+func (r *CertReloader) loadLocked() error {
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS key pair: %w", err)
+	}
+	r.cert = &cert
+	r.modTime = modTime
+	return nil
+}
+
+// Returns the most recent modification time of the certificate and key files
+func (r *CertReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, file := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// LoadCertPool reads PEM encoded CA certificates from a file.
+func LoadCertPool(file string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", file)
+	}
+	return pool, nil
+}
+
+// ServerOptions configures TLS for the HTTP server.
+type ServerOptions struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile enables client certificate verification against the CAs in the file.
+	ClientCAFile string
+	// ClientAuth is "none", "optional" (verify when presented) or "require".
+	ClientAuth string
+}
+
+// ServerConfig builds a TLS 1.2+ server configuration with certificate hot reload.
+func ServerConfig(opts ServerOptions) (*tls.Config, error) {
+	reloader, err := NewCertReloader(opts.CertFile, opts.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
+
+	clientAuth, err := parseClientAuth(opts.ClientAuth)
+	if err != nil {
+		return nil, err
+	}
+	if clientAuth != tls.NoClientCert {
+		if opts.ClientCAFile == "" {
+			return nil, fmt.Errorf("a client CA file is required to verify client certificates")
+		}
+		if cfg.ClientCAs, err = LoadCertPool(opts.ClientCAFile); err != nil {
+			return nil, fmt.Errorf("failed to load client CA: %w", err)
+		}
+		cfg.ClientAuth = clientAuth
+	}
+
+	return cfg, nil
+}
+
+// Maps a client authentication mode to its tls.ClientAuthType
+func parseClientAuth(mode string) (tls.ClientAuthType, error) {
+	switch strings.ToLower(mode) {
+	case "", "none":
+		return tls.NoClientCert, nil
+	case "optional":
+		return tls.VerifyClientCertIfGiven, nil
+	case "require":
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return tls.NoClientCert, fmt.Errorf("invalid client auth mode %q: must be none, optional or require", mode)
+	}
+}
+
+// ClientConfig builds a TLS client configuration trusting the CAs in caFile (the system pool when empty)
+// and presenting the key pair when certFile and keyFile are set.
+func ClientConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		pool, err := LoadCertPool(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load CA: %w", err)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client key pair: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
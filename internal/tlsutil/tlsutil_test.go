@@ -0,0 +1,122 @@
+package tlsutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Writes a self-signed certificate for commonName and its key to dir, returning the file paths
+func writeKeyPair(t *testing.T, dir, commonName string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	assert.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	assert.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+// Unit test for CertReloader picking up a renewed certificate
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeKeyPair(t, dir, "old.example.com")
+
+	reloader, err := NewCertReloader(certFile, keyFile)
+	assert.NoError(t, err)
+	now := time.Now()
+	reloader.now = func() time.Time { return now }
+
+	cert, err := reloader.GetCertificate(nil)
+	assert.NoError(t, err)
+	leaf, _ := x509.ParseCertificate(cert.Certificate[0])
+	assert.Equal(t, "old.example.com", leaf.Subject.CommonName)
+
+	// Renew the certificate with a later modification time
+	writeKeyPair(t, dir, "new.example.com")
+	later := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(certFile, later, later))
+	assert.NoError(t, os.Chtimes(keyFile, later, later))
+
+	// Files are not checked again before the interval passes
+	cert, _ = reloader.GetCertificate(nil)
+	leaf, _ = x509.ParseCertificate(cert.Certificate[0])
+	assert.Equal(t, "old.example.com", leaf.Subject.CommonName)
+
+	now = now.Add(reloadCheckInterval)
+	cert, _ = reloader.GetCertificate(nil)
+	leaf, _ = x509.ParseCertificate(cert.Certificate[0])
+	assert.Equal(t, "new.example.com", leaf.Subject.CommonName)
+
+	// A broken renewal keeps the previous certificate
+	assert.NoError(t, os.WriteFile(keyFile, []byte("garbage"), 0o600))
+	later = later.Add(time.Minute)
+	assert.NoError(t, os.Chtimes(keyFile, later, later))
+	now = now.Add(reloadCheckInterval)
+	cert, err = reloader.GetCertificate(nil)
+	assert.NoError(t, err)
+	leaf, _ = x509.ParseCertificate(cert.Certificate[0])
+	assert.Equal(t, "new.example.com", leaf.Subject.CommonName)
+}
+
+// Unit test for ServerConfig client authentication modes
+func TestServerConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeKeyPair(t, dir, "api.example.com")
+
+	cfg, err := ServerConfig(ServerOptions{CertFile: certFile, KeyFile: keyFile})
+	assert.NoError(t, err)
+	assert.Equal(t, tls.NoClientCert, cfg.ClientAuth)
+	assert.Equal(t, uint16(tls.VersionTLS12), cfg.MinVersion)
+
+	cfg, err = ServerConfig(ServerOptions{CertFile: certFile, KeyFile: keyFile, ClientCAFile: certFile, ClientAuth: "require"})
+	assert.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, cfg.ClientAuth)
+	assert.NotNil(t, cfg.ClientCAs)
+
+	_, err = ServerConfig(ServerOptions{CertFile: certFile, KeyFile: keyFile, ClientAuth: "optional"})
+	assert.Error(t, err)
+
+	_, err = ServerConfig(ServerOptions{CertFile: certFile, KeyFile: keyFile, ClientAuth: "sometimes"})
+	assert.Error(t, err)
+}
+
+// Unit test for ClientConfig
+func TestClientConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeKeyPair(t, dir, "client")
+
+	cfg, err := ClientConfig(certFile, certFile, keyFile)
+	assert.NoError(t, err)
+	assert.NotNil(t, cfg.RootCAs)
+	assert.Len(t, cfg.Certificates, 1)
+
+	_, err = ClientConfig(keyFile, "", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+// Package bic parses and validates SWIFT/BIC codes (ISO 9362) without any network access, and checks whether
+// they exist in a directory snapshot of the SWIFT codes served by the API, embedded in the package.
+//
+//	code, err := bic.Parse("ALBPPLPWXXX")
+//	if err == nil && !code.IsTest() && bic.Exists(code.String()) { ... }
+package bic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalid is matched by the errors of Parse.
+var ErrInvalid = errors.New("invalid BIC")
+
+// BIC is a SWIFT/BIC code split into its parts.
+type BIC struct {
+	// Institution is the 4-letter code of the bank.
+	Institution string
+	// Country is the 2-letter ISO 3166 country code.
+	Country string
+	// Location is the 2-character location code.
+	Location string
+	// Branch is the 3-character branch code, "XXX" for the primary office, or empty for 8-character codes.
+	Branch string
+}
+
+// Parse splits a BIC into its parts, ignoring surrounding spaces and case. It returns an error matching
+// ErrInvalid when the code is not an 8 or 11-character BIC.
+func Parse(code string) (BIC, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 8 && len(code) != 11 {
+		return BIC{}, invalid(code, "must be 8 or 11 characters")
+	}
+
+	b := BIC{Institution: code[:4], Country: code[4:6], Location: code[6:8], Branch: code[8:]}
+	switch {
+	case !letters(b.Institution):
+		return BIC{}, invalid(code, "institution code must be 4 letters")
+	case !letters(b.Country):
+		return BIC{}, invalid(code, "country code must be 2 letters")
+	case !alphanumeric(b.Location):
+		return BIC{}, invalid(code, "location code must be 2 letters or digits")
+	case !alphanumeric(b.Branch):
+		return BIC{}, invalid(code, "branch code must be 3 letters or digits")
+	case b.Branch != "" && b.Branch[0] == 'X' && b.Branch != "XXX":
+		return BIC{}, invalid(code, `branch code starting with "X" must be "XXX"`)
+	}
+	return b, nil
+}
+
+// Valid reports whether code is a well-formed BIC, as accepted by Parse.
+func Valid(code string) bool {
+	_, err := Parse(code)
+	return err == nil
+}
+
+// ValidInstitution reports whether code is a 4-letter institution code, the first part of a BIC. Unlike
+// Parse, it does not ignore spaces or case.
+func ValidInstitution(code string) bool {
+	return len(code) == 4 && letters(code)
+}
+
+// String returns the code as given, 8 or 11 characters.
+func (b BIC) String() string {
+	return b.Institution + b.Country + b.Location + b.Branch
+}
+
+// BIC8 returns the 8-character code of the institution at its location.
+func (b BIC) BIC8() string {
+	return b.Institution + b.Country + b.Location
+}
+
+// BIC11 returns the 11-character code, with branch "XXX" for 8-character codes.
+func (b BIC) BIC11() string {
+	if b.Branch == "" {
+		return b.BIC8() + "XXX"
+	}
+	return b.String()
+}
+
+// IsPrimaryOffice reports whether the code identifies the primary office (headquarters) of the institution.
+func (b BIC) IsPrimaryOffice() bool {
+	return b.Branch == "" || b.Branch == "XXX"
+}
+
+// IsTest reports whether the code is a test and training BIC, whose location code ends with "0".
+func (b BIC) IsTest() bool {
+	return b.Location[1] == '0'
+}
+
+func invalid(code, reason string) error {
+	return fmt.Errorf("%w %q: %s", ErrInvalid, code, reason)
+}
+
+func letters(s string) bool {
+	for _, c := range s {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func alphanumeric(s string) bool {
+	for _, c := range s {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+package bic
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Unit test for Parse
+func TestParse(t *testing.T) {
+	b, err := Parse(" albpplpwxxx ")
+	assert.NoError(t, err)
+	assert.Equal(t, BIC{Institution: "ALBP", Country: "PL", Location: "PW", Branch: "XXX"}, b)
+	assert.Equal(t, "ALBPPLPWXXX", b.String())
+	assert.Equal(t, "ALBPPLPW", b.BIC8())
+	assert.True(t, b.IsPrimaryOffice())
+	assert.False(t, b.IsTest())
+
+	b, err = Parse("ALBPPLP1")
+	assert.NoError(t, err)
+	assert.Equal(t, "ALBPPLP1XXX", b.BIC11())
+	assert.True(t, b.IsPrimaryOffice())
+
+	b, err = Parse("ALBPPL30ABC")
+	assert.NoError(t, err)
+	assert.False(t, b.IsPrimaryOffice())
+	assert.True(t, b.IsTest())
+
+	for code, reason := range map[string]string{
+		"ALBPPLPWXX":  "must be 8 or 11 characters",
+		"ALB1PLPWXXX": "institution code must be 4 letters",
+		"ALBPP1PWXXX": "country code must be 2 letters",
+		"ALBPPLP-XXX": "location code must be 2 letters or digits",
+		"ALBPPLPWAB_": "branch code must be 3 letters or digits",
+		"ALBPPLPWXAB": `branch code starting with "X" must be "XXX"`,
+	} {
+		_, err := Parse(code)
+		assert.ErrorIs(t, err, ErrInvalid, code)
+		assert.ErrorContains(t, err, reason, code)
+		assert.False(t, Valid(code), code)
+	}
+}
+
+// Unit test for ValidInstitution
+func TestValidInstitution(t *testing.T) {
+	assert.True(t, ValidInstitution("TPEO"))
+	assert.False(t, ValidInstitution("tpeo"))
+	assert.False(t, ValidInstitution("TPE"))
+	assert.False(t, ValidInstitution("TPE%"))
+}
+
+// Unit test for the directories
+func TestDirectory(t *testing.T) {
+	d := NewDirectory([]string{"ALBPPLPW", "BREXPLPWMBK", "INVALID"})
+	assert.Equal(t, 2, d.Len())
+	assert.True(t, d.Contains("ALBPPLPWXXX"))
+	assert.True(t, d.Contains("albpplpw"))
+	assert.True(t, d.Contains("BREXPLPWMBK"))
+	assert.False(t, d.Contains("BREXPLPW"))
+	assert.False(t, d.Contains("INVALID"))
+
+	d, err := ReadDirectory(strings.NewReader("# comment\nALBPPLPWXXX\n\nBREXPLPWMBK\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, d.Len())
+
+	_, err = ReadDirectory(strings.NewReader("ALBPPLPWXXX\nALBPPL\n"))
+	assert.ErrorIs(t, err, ErrInvalid)
+	assert.ErrorContains(t, err, "line 2")
+
+	// The embedded snapshot holds the banks data
+	assert.Greater(t, Default().Len(), 1000)
+	assert.True(t, Exists("AAISALTRXXX"))
+	assert.True(t, Exists("AAISALTR"))
+	assert.False(t, Exists("TESTUS33XXX"))
+}
@@ -0,0 +1,92 @@
+package bic
+
+//go:generate go run gen_directory.go -in ../../init.sql -out directory.txt
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Snapshot of the SWIFT codes in the banks data, one 11-character code per line
+//
+//go:embed directory.txt
+var snapshot string
+
+// Directory is a set of known BICs, safe for concurrent use once built.
+type Directory struct {
+	codes map[string]struct{}
+}
+
+// NewDirectory returns a directory of the given codes; codes that are not valid BICs are ignored.
+func NewDirectory(codes []string) *Directory {
+	d := &Directory{codes: make(map[string]struct{}, len(codes))}
+	for _, code := range codes {
+		if b, err := Parse(code); err == nil {
+			d.codes[b.BIC11()] = struct{}{}
+		}
+	}
+	return d
+}
+
+// ReadDirectory reads a directory with one code per line, in the format of the embedded snapshot. Blank
+// lines and lines starting with "#" are skipped.
+func ReadDirectory(r io.Reader) (*Directory, error) {
+	var codes []string
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		code := strings.TrimSpace(scanner.Text())
+		if code == "" || strings.HasPrefix(code, "#") {
+			continue
+		}
+		if !Valid(code) {
+			return nil, fmt.Errorf("line %d: %w %q", line, ErrInvalid, code)
+		}
+		codes = append(codes, code)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewDirectory(codes), nil
+}
+
+// Contains reports whether the directory has the code. An 8-character code matches its primary office.
+func (d *Directory) Contains(code string) bool {
+	b, err := Parse(code)
+	if err != nil {
+		return false
+	}
+	_, ok := d.codes[b.BIC11()]
+	return ok
+}
+
+// Len returns the number of codes in the directory.
+func (d *Directory) Len() int {
+	return len(d.codes)
+}
+
+var (
+	defaultOnce      sync.Once
+	defaultDirectory *Directory
+)
+
+// Default returns the directory embedded in the package, a snapshot of the banks data generated by
+// "go generate ./pkg/bic".
+func Default() *Directory {
+	defaultOnce.Do(func() {
+		d, err := ReadDirectory(strings.NewReader(snapshot))
+		if err != nil {
+			panic("bic: invalid embedded directory: " + err.Error())
+		}
+		defaultDirectory = d
+	})
+	return defaultDirectory
+}
+
+// Exists reports whether the code is in the embedded directory.
+func Exists(code string) bool {
+	return Default().Contains(code)
+}
@@ -0,0 +1,58 @@
+//go:build ignore
+
+// Generates directory.txt from the SWIFT codes of the banks table in the SQL dump loaded into the database.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dodskygge/go_swift/pkg/bic"
+)
+
+// Matches the start of a banks row: (ID, 'country_iso2_code', 'swift_code'
+var rowPattern = regexp.MustCompile(`(?m)^\(\d+, '[^']*', '([^']*)'`)
+
+func main() {
+	in := flag.String("in", "init.sql", "SQL dump with the banks data")
+	out := flag.String("out", "directory.txt", "directory file to write")
+	flag.Parse()
+
+	dump, err := os.ReadFile(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	seen := make(map[string]bool)
+	var codes []string
+	for _, match := range rowPattern.FindAllStringSubmatch(string(dump), -1) {
+		code, err := bic.Parse(match[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !seen[code.BIC11()] {
+			seen[code.BIC11()] = true
+			codes = append(codes, code.BIC11())
+		}
+	}
+	if len(codes) == 0 {
+		log.Fatalf("no SWIFT codes found in %s", *in)
+	}
+	sort.Strings(codes)
+
+	var b strings.Builder
+	b.WriteString("# Code generated by gen_directory.go from the banks data; DO NOT EDIT.\n")
+	for _, code := range codes {
+		b.WriteString(code)
+		b.WriteByte('\n')
+	}
+	if err := os.WriteFile(*out, []byte(b.String()), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("wrote %d codes to %s\n", len(codes), *out)
+}
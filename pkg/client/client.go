@@ -0,0 +1,385 @@
+// Package client is a Go client for the SWIFT codes REST API.
+//
+//	c, err := client.New("https://swift.example.com", client.WithAPIKey(key))
+//	code, err := c.GetSwiftCode(ctx, "ALBPPLPWXXX")
+//	if errors.Is(err, client.ErrNotFound) { ... }
+//
+// Requests are retried with exponential backoff on network errors, 429 Too Many Requests and 502, 503 and 504
+// responses, honoring Retry-After. Creates and bulk writes carry a generated Idempotency-Key so that retrying them is safe.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dodskygge/go_swift/internal/model"
+)
+
+// Types shared with the server.
+type (
+	// SwiftCode is a SWIFT code with its branches. ETag, Version and LastModified are taken from the ETag and
+	// Last-Modified headers, which headquarters are sent without; pass ETag to Delete.
+	SwiftCode = model.SwiftCodeResponse
+	// Branch is a branch of a headquarters SWIFT code.
+	Branch = model.SwiftCodeBranch
+	// Country lists the SWIFT codes of a country.
+	Country = model.SwiftCodesByCountryResponse
+	// CountrySwiftCode is a SWIFT code listed for a country.
+	CountrySwiftCode = model.SwiftCodeMinimalResponse
+	// CreateRequest holds a new SWIFT code, and is the record format of imports and exports.
+	CreateRequest = model.CreateSwiftCodeRequest
+	// BulkWriteResponse reports the outcome of each record of a BulkWrite.
+	BulkWriteResponse = model.BulkWriteResponse
+	// BulkItemResult is the outcome of one record of a BulkWrite.
+	BulkItemResult = model.BulkItemResult
+	// Filter selects the SWIFT codes to export; zero values match every code.
+	Filter = model.SwiftCodeFilter
+	// Search selects one page of SWIFT codes by code prefix or bank name, filter, After and Limit.
+	Search = model.SwiftCodeSearch
+	// SearchResult is one page of search results, with the value of After for the next page in Next.
+	SearchResult = model.SwiftCodeSearchResponse
+)
+
+// Modes of BulkWrite.
+const (
+	BulkAllOrNothing = model.BulkAllOrNothing
+	BulkBestEffort   = model.BulkBestEffort
+)
+
+// Defaults used unless changed with options.
+const (
+	DefaultTimeout    = 10 * time.Second
+	DefaultMaxRetries = 3
+	DefaultBackoff    = 100 * time.Millisecond
+	// Longest wait between two attempts, including waits requested with Retry-After
+	maxBackoff = 30 * time.Second
+)
+
+// Client calls the SWIFT codes API. It is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	apiKey     string
+	token      string
+	userAgent  string
+	maxRetries int
+	backoff    time.Duration
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sends requests with hc instead of a client with DefaultTimeout.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithTimeout limits each attempt of a request, including reading the response body.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		hc := *c.httpClient
+		hc.Timeout = timeout
+		c.httpClient = &hc
+	}
+}
+
+// WithAPIKey sends key in the X-API-Key header.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithBearerToken sends token in the Authorization header.
+func WithBearerToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithUserAgent sets the User-Agent header.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// WithRetries retries failed attempts up to maxRetries times, waiting backoff before the first retry and
+// doubling the wait for each further one. Zero maxRetries disables retries.
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.backoff = backoff
+	}
+}
+
+// New returns a client for the API served at baseURL, e.g. "http://localhost:8080".
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		userAgent:  "go_swift-client",
+		maxRetries: DefaultMaxRetries,
+		backoff:    DefaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// GetSwiftCode returns a SWIFT code with its branches, or an error matching ErrNotFound.
+func (c *Client) GetSwiftCode(ctx context.Context, swiftCode string) (*SwiftCode, error) {
+	var code SwiftCode
+	resp, err := c.do(ctx, http.MethodGet, "/api/v1/swift-codes/"+url.PathEscape(swiftCode), nil, nil, &code)
+	if err != nil {
+		return nil, err
+	}
+
+	code.ETag = resp.Header.Get("ETag")
+	code.Version = etagVersion(code.ETag)
+	if lastModified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		code.LastModified = lastModified
+	}
+	return &code, nil
+}
+
+// GetByCountry returns the SWIFT codes of a country, given by its ISO2 code. A country without codes is
+// returned with an empty list.
+func (c *Client) GetByCountry(ctx context.Context, countryISO2 string) (*Country, error) {
+	var country Country
+	if _, err := c.do(ctx, http.MethodGet, "/api/v1/swift-codes/country/"+url.PathEscape(countryISO2), nil, nil, &country); err != nil {
+		return nil, err
+	}
+	return &country, nil
+}
+
+// Search returns one page of the SWIFT codes, without branches, whose code starts with search.Query or
+// whose bank name contains it, ordered by code. Next of the result is empty on the last page.
+func (c *Client) Search(ctx context.Context, search Search) (*SearchResult, error) {
+	query := url.Values{}
+	if search.Query != "" {
+		query.Set("q", search.Query)
+	}
+	if search.Filter.CountryISO2 != "" {
+		query.Set("country", search.Filter.CountryISO2)
+	}
+	if search.Filter.IsHeadquarter != nil {
+		query.Set("hq", strconv.FormatBool(*search.Filter.IsHeadquarter))
+	}
+	if search.After != "" {
+		query.Set("after", search.After)
+	}
+	if search.Limit > 0 {
+		query.Set("limit", strconv.Itoa(search.Limit))
+	}
+
+	var result SearchResult
+	if _, err := c.do(ctx, http.MethodGet, "/api/v1/swift-codes/search?"+query.Encode(), nil, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Create stores a new SWIFT code. Retries reuse one Idempotency-Key, so the code is created at most once.
+func (c *Client) Create(ctx context.Context, req CreateRequest) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	header, err := idempotencyHeader()
+	if err != nil {
+		return err
+	}
+	_, err = c.do(ctx, http.MethodPost, "/api/v1/swift-codes", header, body, nil)
+	return err
+}
+
+// BulkWrite creates many SWIFT codes in one transaction, updating existing ones when upsert is set. In
+// BulkAllOrNothing mode nothing is written unless every record succeeds; the response then has Committed
+// unset and the failed records marked. Like Create, retries reuse one Idempotency-Key.
+func (c *Client) BulkWrite(ctx context.Context, records []CreateRequest, mode string, upsert bool) (*BulkWriteResponse, error) {
+	body, err := json.Marshal(records)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+	header, err := idempotencyHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"mode": {mode}}
+	if upsert {
+		query.Set("upsert", "true")
+	}
+	var result BulkWriteResponse
+	_, err = c.do(ctx, http.MethodPost, "/api/v1/swift-codes/bulk?"+query.Encode(), header, body, &result)
+
+	// Uncommitted writes are answered with 422 and the per-record outcome
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity &&
+		json.Unmarshal(apiErr.body, &result) == nil && result.Mode != "" {
+		return &result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Export calls fn for every SWIFT code matching the filter, ordered by code, as the records are received.
+// An error from fn stops the export and is returned. The client timeout applies to the whole export.
+func (c *Client) Export(ctx context.Context, filter Filter, fn func(CreateRequest) error) error {
+	query := url.Values{"format": {"ndjson"}}
+	if filter.CountryISO2 != "" {
+		query.Set("country", filter.CountryISO2)
+	}
+	if filter.IsHeadquarter != nil {
+		query.Set("hq", strconv.FormatBool(*filter.IsHeadquarter))
+	}
+
+	header := http.Header{"Accept": {"application/x-ndjson"}}
+	resp, err := c.send(ctx, http.MethodGet, "/api/v1/swift-codes/export?"+query.Encode(), header, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var record CreateRequest
+		err := decoder.Decode(&record)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode export: %w", err)
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+}
+
+// Returns the header of a request that is safe to retry, with a random Idempotency-Key
+func idempotencyHeader() (http.Header, error) {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate idempotency key: %w", err)
+	}
+	return http.Header{"Idempotency-Key": {hex.EncodeToString(key)}}, nil
+}
+
+// Delete removes a SWIFT code provided it is unchanged since it was returned by GetSwiftCode with the
+// given etag, SwiftCode.ETag, and returns an error matching ErrVersionMismatch otherwise. An empty etag
+// deletes the code whatever its version.
+func (c *Client) Delete(ctx context.Context, swiftCode, etag string) error {
+	ifMatch := etag
+	if ifMatch == "" {
+		ifMatch = "*"
+	}
+	header := http.Header{"If-Match": {ifMatch}}
+	_, err := c.do(ctx, http.MethodDelete, "/api/v1/swift-codes/"+url.PathEscape(swiftCode), header, nil, nil)
+	return err
+}
+
+// Sends a request, retrying it when allowed, and decodes a successful JSON response into out when set.
+// Error responses are returned as *Error.
+func (c *Client) do(ctx context.Context, method, path string, header http.Header, body []byte, out any) (*http.Response, error) {
+	resp, err := c.send(ctx, method, path, header, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return resp, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return resp, nil
+}
+
+// Sends a request until it gets a successful response, whose body the caller must close, or a failure
+// that is not retried. Error responses are returned as *Error.
+func (c *Client) send(ctx context.Context, method, path string, header http.Header, body []byte) (*http.Response, error) {
+	target := c.baseURL.String() + path
+	// Reads, and writes the server deduplicates by their Idempotency-Key, may be sent again whatever happened
+	replayable := method == http.MethodGet || method == http.MethodHead || header.Get("Idempotency-Key") != ""
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		if req.Header.Get("Accept") == "" {
+			req.Header.Set("Accept", "application/json")
+		}
+		req.Header.Set("User-Agent", c.userAgent)
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if c.apiKey != "" {
+			req.Header.Set("X-API-Key", c.apiKey)
+		}
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err == nil {
+			if resp.StatusCode < 300 {
+				return resp, nil
+			}
+			err = newError(resp)
+			resp.Body.Close()
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if attempt >= c.maxRetries || !retryable(err, replayable) {
+			return nil, err
+		}
+
+		wait := c.backoff << attempt
+		var apiErr *Error
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > wait {
+			wait = apiErr.RetryAfter
+		}
+		timer := time.NewTimer(min(wait, maxBackoff))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Returns the version embedded in an ETag of the form "<version>-<hash>", 0 when there is none
+func etagVersion(etag string) int64 {
+	prefix, _, found := strings.Cut(strings.Trim(etag, `"`), "-")
+	if !found {
+		return 0
+	}
+	version, _ := strconv.ParseInt(prefix, 10, 64)
+	return version
+}
@@ -0,0 +1,270 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Starts a test server and returns a client for it that retries without waiting
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c, err := New(server.URL, append([]Option{WithRetries(2, time.Millisecond)}, opts...)...)
+	assert.NoError(t, err)
+	return c
+}
+
+// Unit test for New
+func TestNew(t *testing.T) {
+	_, err := New("localhost:8080")
+	assert.Error(t, err)
+
+	c, err := New("http://localhost:8080/", WithTimeout(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080", c.baseURL.String())
+	assert.Equal(t, time.Second, c.httpClient.Timeout)
+}
+
+// Unit test for GetSwiftCode
+func TestGetSwiftCode(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/swift-codes/TESTUS33XXX", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("X-API-Key"))
+		assert.Equal(t, "application/json", r.Header.Get("Accept"))
+
+		w.Header().Set("ETag", `"3-0123456789abcdef"`)
+		w.Header().Set("Last-Modified", "Tue, 01 Apr 2025 10:00:00 GMT")
+		w.Write([]byte(`{"address":"123 Main St","bankName":"Test Bank","countryISO2":"US","countryName":"UNITED STATES","isHeadquarter":true,"swiftCode":"TESTUS33XXX","branches":[{"address":"456 Branch St","bankName":"Test Bank","countryISO2":"US","isHeadquarter":false,"swiftCode":"TESTUS33ABC"}]}`))
+	}, WithAPIKey("secret"))
+
+	code, err := c.GetSwiftCode(context.Background(), "TESTUS33XXX")
+	assert.NoError(t, err)
+	assert.Equal(t, "Test Bank", code.BankName)
+	assert.Equal(t, []Branch{{Address: "456 Branch St", BankName: "Test Bank", CountryISO2: "US", SwiftCode: "TESTUS33ABC"}}, code.Branches)
+	assert.Equal(t, int64(3), code.Version)
+	assert.Equal(t, time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC), code.LastModified)
+}
+
+// Unit test for GetByCountry
+func TestGetByCountry(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/swift-codes/country/PL", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"countryISO2":"PL","countryName":"POLAND","swiftCodes":[{"address":"1 Prosta","bankName":"Test Bank","countryISO2":"PL","isHeadquarter":true,"swiftCode":"TESTPLPWXXX"}]}`))
+	}, WithBearerToken("token"))
+
+	country, err := c.GetByCountry(context.Background(), "PL")
+	assert.NoError(t, err)
+	assert.Equal(t, "POLAND", country.CountryName)
+	assert.Equal(t, []CountrySwiftCode{{Address: "1 Prosta", BankName: "Test Bank", CountryISO2: "PL", IsHeadquarter: true, SwiftCode: "TESTPLPWXXX"}}, country.SwiftCodes)
+}
+
+// Unit test for Search
+func TestSearch(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/swift-codes/search", r.URL.Path)
+		assert.Equal(t, "after=AAAAPLPWXXX&country=PL&hq=true&limit=1&q=bank", r.URL.RawQuery)
+		w.Write([]byte(`{"swiftCodes":[{"address":"2","bankName":"B Bank","countryISO2":"PL","countryName":"POLAND","isHeadquarter":true,"swiftCode":"BBBBPLPWXXX"}],"next":"BBBBPLPWXXX"}`))
+	})
+
+	hq := true
+	result, err := c.Search(context.Background(), Search{Query: "bank", Filter: Filter{CountryISO2: "PL", IsHeadquarter: &hq}, After: "AAAAPLPWXXX", Limit: 1})
+	assert.NoError(t, err)
+	if assert.Len(t, result.SwiftCodes, 1) {
+		assert.Equal(t, "BBBBPLPWXXX", result.SwiftCodes[0].SwiftCode)
+	}
+	assert.Equal(t, "BBBBPLPWXXX", result.Next)
+}
+
+// Unit test for Create retries reusing the idempotency key
+func TestCreate(t *testing.T) {
+	var attempts atomic.Int32
+	var keys []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+
+		var req CreateRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "TESTUS33XXX", req.SwiftCode)
+
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"message":"SWIFT code created successfully"}`))
+	})
+
+	err := c.Create(context.Background(), CreateRequest{SwiftCode: "TESTUS33XXX", BankName: "Test Bank", CountryISO2: "US"})
+	assert.NoError(t, err)
+	if assert.Len(t, keys, 2) {
+		assert.NotEmpty(t, keys[0])
+		assert.Equal(t, keys[0], keys[1])
+	}
+}
+
+// Unit test for Delete
+func TestDelete(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Header().Set("ETag", `"3-0123456789abcdef"`)
+			w.Write([]byte(`{"swiftCode":"TESTUS33XXX"}`))
+			return
+		}
+		assert.Equal(t, http.MethodDelete, r.Method)
+		switch r.Header.Get("If-Match") {
+		case `"3-0123456789abcdef"`, "*":
+			w.Write([]byte(`{"message":"SWIFT code deleted successfully"}`))
+		default:
+			w.WriteHeader(http.StatusPreconditionFailed)
+			w.Write([]byte(`{"error":"SWIFT code has been modified"}`))
+		}
+	})
+
+	// The ETag of the code is sent back as is
+	code, err := c.GetSwiftCode(context.Background(), "TESTUS33XXX")
+	assert.NoError(t, err)
+	assert.Equal(t, `"3-0123456789abcdef"`, code.ETag)
+	assert.Equal(t, int64(3), code.Version)
+	assert.NoError(t, c.Delete(context.Background(), "TESTUS33XXX", code.ETag))
+	assert.NoError(t, c.Delete(context.Background(), "TESTUS33XXX", ""))
+
+	err = c.Delete(context.Background(), "TESTUS33XXX", `"2-0123456789abcdef"`)
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	var apiErr *Error
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.Equal(t, http.StatusPreconditionFailed, apiErr.StatusCode)
+		assert.Equal(t, "SWIFT code has been modified", apiErr.Message)
+	}
+}
+
+// Unit test for error decoding and retries
+func TestErrors(t *testing.T) {
+	var attempts atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		switch r.URL.Path {
+		case "/api/v1/swift-codes/MISSUS33XXX":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"SWIFT code not found"}`))
+		case "/api/v1/swift-codes/country/US":
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("forbidden"))
+		}
+	})
+
+	// Client errors are not retried
+	_, err := c.GetSwiftCode(context.Background(), "MISSUS33XXX")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.EqualError(t, err, "swift api: 404 SWIFT code not found")
+	assert.Equal(t, int32(1), attempts.Swap(0))
+
+	_, err = c.GetSwiftCode(context.Background(), "TESTUS33XXX")
+	assert.ErrorIs(t, err, ErrForbidden)
+	assert.EqualError(t, err, "swift api: 403 Forbidden")
+	assert.Equal(t, int32(1), attempts.Swap(0))
+
+	// Rate limited requests are retried up to the limit
+	_, err = c.GetByCountry(context.Background(), "US")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, int32(3), attempts.Swap(0))
+}
+
+// Unit test for timeouts and cancellation
+func TestTimeouts(t *testing.T) {
+	release := make(chan struct{})
+	var attempts atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}, WithTimeout(20*time.Millisecond))
+	defer close(release)
+
+	// Each attempt times out and is retried
+	_, err := c.GetSwiftCode(context.Background(), "TESTUS33XXX")
+	assert.Error(t, err)
+	assert.Equal(t, int32(3), attempts.Swap(0))
+
+	// A delete may have been processed before timing out, so it is not sent again
+	err = c.Delete(context.Background(), "TESTUS33XXX", "")
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), attempts.Swap(0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = c.GetSwiftCode(ctx, "TESTUS33XXX")
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, int32(0), attempts.Load())
+}
+
+// Unit test for BulkWrite
+func TestBulkWrite(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/swift-codes/bulk", r.URL.Path)
+		assert.NotEmpty(t, r.Header.Get("Idempotency-Key"))
+
+		var records []CreateRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&records))
+		if r.URL.Query().Get("upsert") == "true" {
+			w.Write([]byte(`{"mode":"best-effort","committed":true,"created":1,"updated":1,"failed":0,"items":[]}`))
+			return
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"mode":"all-or-nothing","committed":false,"created":0,"updated":0,"failed":1,"items":[{"index":0,"swiftCode":"TESTUS33XXX","status":"failed","error":"SWIFT code already exists"}]}`))
+	})
+
+	records := []CreateRequest{{SwiftCode: "TESTUS33XXX"}, {SwiftCode: "TESTPLPWXXX"}}
+	result, err := c.BulkWrite(context.Background(), records, BulkBestEffort, true)
+	assert.NoError(t, err)
+	assert.True(t, result.Committed)
+	assert.Equal(t, 1, result.Updated)
+
+	// Uncommitted writes are reported in the response rather than as an error
+	result, err = c.BulkWrite(context.Background(), records, BulkAllOrNothing, false)
+	assert.NoError(t, err)
+	assert.False(t, result.Committed)
+	assert.Equal(t, "SWIFT code already exists", result.Items[0].Error)
+}
+
+// Unit test for Export
+func TestExport(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "ndjson", r.URL.Query().Get("format"))
+		assert.Equal(t, "PL", r.URL.Query().Get("country"))
+		assert.Equal(t, "true", r.URL.Query().Get("hq"))
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Write([]byte(`{"swiftCode":"AAAAPLPWXXX","bankName":"A","address":"1","countryISO2":"PL","countryName":"POLAND","isHeadquarter":true}` + "\n"))
+		w.Write([]byte(`{"swiftCode":"BBBBPLPWXXX","bankName":"B","address":"2","countryISO2":"PL","countryName":"POLAND","isHeadquarter":true}` + "\n"))
+	})
+
+	hq := true
+	var codes []string
+	err := c.Export(context.Background(), Filter{CountryISO2: "PL", IsHeadquarter: &hq}, func(record CreateRequest) error {
+		codes = append(codes, record.SwiftCode)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"AAAAPLPWXXX", "BBBBPLPWXXX"}, codes)
+
+	// Errors from the callback stop the export
+	stop := errors.New("stop")
+	err = c.Export(context.Background(), Filter{CountryISO2: "PL", IsHeadquarter: &hq}, func(CreateRequest) error { return stop })
+	assert.ErrorIs(t, err, stop)
+}
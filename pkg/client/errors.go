@@ -0,0 +1,101 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dodskygge/go_swift/internal/model"
+)
+
+// Errors matched by the *Error of a failed request with errors.Is.
+var (
+	// ErrNotFound matches 404 Not Found responses.
+	ErrNotFound = model.ErrNotFound
+	// ErrVersionMismatch matches 412 Precondition Failed responses to changes of a modified SWIFT code.
+	ErrVersionMismatch = model.ErrVersionMismatch
+	// ErrInvalidInput matches 400 Bad Request responses.
+	ErrInvalidInput = model.ErrInvalidInput
+	// ErrUnauthorized matches 401 Unauthorized responses to requests without valid credentials.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden matches 403 Forbidden responses to credentials lacking the required scope.
+	ErrForbidden = errors.New("forbidden")
+	// ErrRateLimited matches 429 Too Many Requests responses.
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// Statuses of the responses each sentinel error matches
+var statusErrors = map[int]error{
+	http.StatusNotFound:           ErrNotFound,
+	http.StatusPreconditionFailed: ErrVersionMismatch,
+	http.StatusBadRequest:         ErrInvalidInput,
+	http.StatusUnauthorized:       ErrUnauthorized,
+	http.StatusForbidden:          ErrForbidden,
+	http.StatusTooManyRequests:    ErrRateLimited,
+}
+
+// Error is returned for responses with an error status.
+type Error struct {
+	StatusCode int
+	// Message from the error body, or the status text when the body has none
+	Message string
+	// RetryAfter is the wait requested by the Retry-After header, 0 when absent
+	RetryAfter time.Duration
+
+	body []byte
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("swift api: %d %s", e.StatusCode, e.Message)
+}
+
+// Is matches the sentinel error for the status code, e.g. ErrNotFound for 404.
+func (e *Error) Is(target error) bool {
+	return statusErrors[e.StatusCode] == target
+}
+
+// Builds the error for a response with an error status from its model.ErrorResponse body
+func newError(resp *http.Response) *Error {
+	apiErr := &Error{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+
+	apiErr.body, _ = io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	var body model.ErrorResponse
+	if json.Unmarshal(apiErr.body, &body) == nil && body.Message != "" {
+		apiErr.Message = body.Message
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(resp.Header.Get("Retry-After")); err == nil {
+		apiErr.RetryAfter = max(time.Until(at), 0)
+	}
+	return apiErr
+}
+
+// Reports whether a failed attempt may succeed when repeated: rate limiting, unavailable upstreams, and a
+// request with the same Idempotency-Key still being processed. Network errors and gateway failures may
+// come after the request was processed, so they are retried only when replayable, i.e. when repeating the
+// request cannot change data twice.
+func retryable(err error, replayable bool) bool {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		// Responses that cannot be decoded are not transient
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		return replayable && !errors.As(err, &syntaxErr) && !errors.As(err, &typeErr)
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return replayable
+	case http.StatusConflict:
+		return apiErr.RetryAfter > 0
+	}
+	return false
+}